@@ -0,0 +1,70 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// generateMu serializes Generate, as generating is configured by the options
+// the flags of the generate command are bound to.
+var generateMu sync.Mutex
+
+// Generate generates Go code from the MIB modules args like the generate
+// command, configured by flags of the generate command, e.g.
+// "--package=foo", and the generate section of configFile, if given, for the
+// options not given in flags. The options of previous calls and the flags of
+// the generate command on the command line don't carry over. searchPaths are
+// searched for the modules before any other path. It is the core of
+// mib2go.GenerateFromSources.
+func Generate(args []string, flags []string, configFile string, searchPaths ...string) error {
+	generateMu.Lock()
+	defer generateMu.Unlock()
+
+	flagSet := pflag.NewFlagSet("generate", pflag.ContinueOnError)
+	addGenerateFlags(flagSet)
+	err := flagSet.Parse(flags)
+	if err != nil {
+		return errors.Wrap(err, "Parsing flags")
+	}
+	if flagSet.NArg() > 0 {
+		return errors.Errorf("Unexpected argument %s", flagSet.Arg(0))
+	}
+
+	if configFile != "" {
+		config := viper.New()
+		config.SetConfigFile(configFile)
+		err = config.ReadInConfig()
+		if err != nil {
+			return errors.Wrapf(err, "Reading config file %s", configFile)
+		}
+		err = applyConfigSection(config, "generate", flagSet)
+		if err != nil {
+			return err
+		}
+	}
+
+	return generate(args, searchPaths...)
+}
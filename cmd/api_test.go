@@ -0,0 +1,114 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const sourcesTestMib = `SOURCES-TEST-MIB DEFINITIONS ::= BEGIN
+IMPORTS enterprises FROM SNMPv2-SMI;
+sourcesTest OBJECT IDENTIFIER ::= { enterprises 99999 30 }
+END
+`
+
+// generateSources generates the modules given as source text keyed by module
+// name with Generate and the given flags, like mib2go.GenerateFromSources,
+// which imports this package and so can't be used by its tests.
+func generateSources(sources map[string]string, flags []string) error {
+	dir, err := ioutil.TempDir("", "mib2go-sources")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	moduleNames := make([]string, 0, len(sources))
+	for moduleName, source := range sources {
+		err = ioutil.WriteFile(filepath.Join(dir, moduleName), []byte(source), 0644)
+		if err != nil {
+			return err
+		}
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+
+	return Generate(moduleNames, flags, "", dir)
+}
+
+func TestGenerateOptionsDontCarryOver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sources := map[string]string{"SOURCES-TEST-MIB": sourcesTestMib}
+
+	err = generateSources(sources, []string{"--dir", dir, "--oid-type", "bogus"})
+	if err == nil || !strings.Contains(err.Error(), "Invalid OID type bogus") {
+		t.Fatalf("Expected the invalid OID type to be rejected, got %v", err)
+	}
+
+	err = generateSources(sources, []string{"--dir", dir})
+	if err != nil && strings.Contains(err.Error(), "OID type") {
+		t.Errorf("The OID type of the previous call carried over: %v", err)
+	}
+}
+
+func TestGenerateConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "SOURCES-TEST-MIB"), []byte(sourcesTestMib), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configFile := filepath.Join(dir, "config.yaml")
+	err = ioutil.WriteFile(configFile, []byte("generate:\n  oid-type: bogus\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := filepath.Join(dir, "mibs")
+	err = Generate([]string{"SOURCES-TEST-MIB"}, []string{"--dir", outDir}, configFile, dir)
+	if err == nil || !strings.Contains(err.Error(), "Invalid OID type bogus") {
+		t.Fatalf("Expected the OID type of the config file to be used, got %v", err)
+	}
+
+	err = Generate([]string{"SOURCES-TEST-MIB"}, []string{"--dir", outDir, "--oid-type", "models"}, configFile, dir)
+	if err != nil && strings.Contains(err.Error(), "OID type") {
+		t.Errorf("Expected flags to take precedence over the config file, got %v", err)
+	}
+}
+
+func TestGenerateUnexpectedArgument(t *testing.T) {
+	err := Generate(nil, []string{"SOURCES-TEST-MIB"}, "")
+	if err == nil || !strings.Contains(err.Error(), "Unexpected argument SOURCES-TEST-MIB") {
+		t.Fatalf("Expected the argument to be rejected, got %v", err)
+	}
+}
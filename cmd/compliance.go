@@ -0,0 +1,179 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/smi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+const complianceDecls = `// ComplianceNode is a MODULE-COMPLIANCE with the groups and refinements of the
+// modules it covers.
+type ComplianceNode struct {
+	models.BaseNode
+	ID              string
+	Status          types.Status
+	Modules         []string
+	MandatoryGroups []models.BaseNode
+	OptionalGroups  []models.BaseNode
+	Refinements     []Refinement
+}
+
+// Refinement is an OBJECT clause of a MODULE-COMPLIANCE, refining the SYNTAX,
+// WRITE-SYNTAX or MIN-ACCESS an object is required to be implemented with.
+type Refinement struct {
+	Object      models.BaseNode
+	Syntax      *models.Type
+	WriteSyntax *models.Type
+	MinAccess   types.Access
+}
+
+`
+
+// refinement is an OBJECT clause of a MODULE-COMPLIANCE.
+type refinement struct {
+	object      gosmi.SmiNode
+	syntax      *models.Type
+	writeSyntax *models.Type
+	minAccess   types.Access
+}
+
+// generateCompliance emits the fields of a MODULE-COMPLIANCE node. gosmi only
+// models its mandatory groups, which it lists as its elements, so the GROUP
+// and OBJECT clauses are read from the raw node with the smi package.
+func generateCompliance(buf io.Writer, shared *sharedDecls, compliance gosmi.SmiNode) {
+	shared.compliances = true
+	mandatory := compliance.GetNotificationObjects()
+	optional := complianceOptions(compliance)
+	refinements := complianceRefinements(compliance)
+
+	covered := make(map[string]bool)
+	for _, nodes := range [][]gosmi.SmiNode{mandatory, optional} {
+		for _, node := range nodes {
+			covered[node.GetModule().Name] = true
+		}
+	}
+	for _, refinement := range refinements {
+		covered[refinement.object.GetModule().Name] = true
+	}
+	moduleNames := make([]string, 0, len(covered))
+	for moduleName := range covered {
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+	fmt.Fprintf(buf, "\tModules: %#v,\n", moduleNames)
+
+	for _, groups := range []struct {
+		field string
+		nodes []gosmi.SmiNode
+	}{{"MandatoryGroups", mandatory}, {"OptionalGroups", optional}} {
+		if len(groups.nodes) == 0 {
+			continue
+		}
+		shared.groups = true
+		fmt.Fprintf(buf, "\t%s: []models.BaseNode{\n", groups.field)
+		for _, group := range groups.nodes {
+			if !skipped(group) {
+				fmt.Fprintf(buf, "\t\t%s.BaseNode,\n", shared.refVarName(group))
+			}
+		}
+		fmt.Fprintf(buf, "\t},\n")
+	}
+
+	if len(refinements) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "\tRefinements: []Refinement{\n")
+	for _, refinement := range refinements {
+		if skipped(refinement.object) {
+			continue
+		}
+		fmt.Fprintf(buf, "\t\t{\n")
+		fmt.Fprintf(buf, "\t\t\tObject: %s.BaseNode,\n", shared.refVarName(refinement.object))
+		for _, syntax := range []struct {
+			field string
+			t     *models.Type
+		}{{"Syntax", refinement.syntax}, {"WriteSyntax", refinement.writeSyntax}} {
+			if syntax.t != nil {
+				fmt.Fprintf(buf, "\t\t\t%s: &models.Type{\n", syntax.field)
+				generateTypeFields(buf, syntax.t)
+				fmt.Fprintf(buf, "\t\t\t},\n")
+			}
+		}
+		if refinement.minAccess != types.AccessUnknown {
+			fmt.Fprintf(buf, "\t\t\tMinAccess: types.Access%s,\n", refinement.minAccess)
+		}
+		fmt.Fprintf(buf, "\t\t},\n")
+	}
+	fmt.Fprintf(buf, "\t},\n")
+}
+
+// complianceOptions returns the groups of the GROUP clauses of compliance,
+// which are only required under the conditions they describe.
+func complianceOptions(compliance gosmi.SmiNode) (groups []gosmi.SmiNode) {
+	for option := smi.GetFirstOption(compliance.GetRaw()); option != nil; option = smi.GetNextOption(option) {
+		if group := smi.GetOptionNode(option); group != nil {
+			groups = append(groups, gosmi.CreateNode(group))
+		}
+	}
+	return groups
+}
+
+// complianceRefinements returns the OBJECT clauses of compliance.
+func complianceRefinements(compliance gosmi.SmiNode) (refinements []refinement) {
+	for raw := smi.GetFirstRefinement(compliance.GetRaw()); raw != nil; raw = smi.GetNextRefinement(raw) {
+		object := smi.GetRefinementNode(raw)
+		if object == nil {
+			continue
+		}
+		refinements = append(refinements, refinement{
+			object:      gosmi.CreateNode(object),
+			syntax:      refinedType(smi.GetRefinementType(raw)),
+			writeSyntax: refinedType(smi.GetRefinementWriteType(raw)),
+			minAccess:   raw.Access,
+		})
+	}
+	return refinements
+}
+
+// refinedType converts the type of a refined SYNTAX, which usually is an
+// anonymous restriction of the type of the object. Anonymous types are named
+// after the type they restrict.
+func refinedType(raw *types.SmiType) *models.Type {
+	if raw == nil {
+		return nil
+	}
+
+	t := gosmi.CreateType(raw).Type
+	for parent := raw; parent != nil && t.Name == ""; parent = smi.GetParentType(parent) {
+		t.Name = string(parent.Name)
+	}
+	if t.Name == "" {
+		t.Name = t.BaseType.String()
+	}
+	return &t
+}
@@ -0,0 +1,72 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// nodeDefault returns a Go expression for the DEFVAL of node, if it has one.
+// The gosmi model of a node has no field for DEFVAL, so it is taken from the
+// value of the raw gosmi node. Integers become int64 or uint64, enumerations
+// the int64 value of their label, octet strings and BITS a []byte and object
+// identifiers a models.Oid.
+func nodeDefault(node gosmi.SmiNode) (string, bool) {
+	raw := node.GetRaw()
+	if raw == nil || raw.Value.Value == nil {
+		return "", false
+	}
+
+	value := reflect.ValueOf(raw.Value.Value)
+	switch raw.Value.BaseType {
+	case types.BaseTypeInteger32, types.BaseTypeInteger64, types.BaseTypeEnum:
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return fmt.Sprintf("int64(%d)", value.Int()), true
+		}
+	case types.BaseTypeUnsigned32, types.BaseTypeUnsigned64:
+		switch value.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return fmt.Sprintf("uint64(%d)", value.Uint()), true
+		}
+	case types.BaseTypeOctetString, types.BaseTypeBits:
+		switch {
+		case value.Kind() == reflect.String:
+			return fmt.Sprintf("[]byte(%q)", value.String()), true
+		case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+			return fmt.Sprintf("[]byte(%q)", value.Bytes()), true
+		}
+	case types.BaseTypeObjectIdentifier:
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint32 {
+			oid := make(models.Oid, value.Len())
+			for i := range oid {
+				oid[i] = uint32(value.Index(i).Uint())
+			}
+			return fmt.Sprintf("models.Oid{%s}", formatSubIDs(oid)), true
+		}
+	}
+	return "", false
+}
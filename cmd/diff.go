@@ -0,0 +1,244 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+	"github.com/spf13/cobra"
+)
+
+var diffFormat string
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff OLD NEW",
+	Short: "Compares two revisions of a MIB",
+	Long: `Loads two revisions of a MIB, given as module names or files, one after the
+other and reports the nodes added and removed by the new revision, and the
+nodes whose OID, type, access or enumeration values changed, in the order of
+their OIDs. With --format json, the changes are written as a JSON array.`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := applyConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		return diff(args[0], args[1], os.Stdout)
+	},
+}
+
+// diffNode is what is compared of a node. Nodes are copied out of libsmi, as
+// the revisions usually share their module name, so they can't be loaded at
+// the same time.
+type diffNode struct {
+	oid    models.Oid
+	kind   types.NodeKind
+	typ    string
+	access types.Access
+	enum   models.EnumValues
+}
+
+// moduleChange is a difference between two revisions of a module.
+type moduleChange struct {
+	Node   string `json:"node"`
+	Change string `json:"change"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+	oid    models.Oid
+}
+
+func diff(oldArg string, newArg string, out io.Writer) error {
+	if diffFormat != "text" && diffFormat != "json" {
+		return errors.Errorf("Invalid format %s", diffFormat)
+	}
+
+	tempDir, err := ioutil.TempDir("", "mib2go")
+	if err != nil {
+		return errors.Wrap(err, "Creating temporary directory")
+	}
+	defer os.RemoveAll(tempDir)
+	defer removeOnInterrupt(tempDir)()
+
+	oldNodes, err := loadDiffNodes(oldArg, tempDir)
+	if err != nil {
+		return err
+	}
+	newNodes, err := loadDiffNodes(newArg, tempDir)
+	if err != nil {
+		return err
+	}
+
+	changes := diffNodes(oldNodes, newNodes)
+	if diffFormat == "json" {
+		if changes == nil {
+			changes = []moduleChange{}
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return errors.Wrap(encoder.Encode(changes), "Encoding changes")
+	}
+
+	for _, change := range changes {
+		switch change.Change {
+		case "added":
+			fmt.Fprintf(out, "+ %s %s\n", change.Node, change.New)
+		case "removed":
+			fmt.Fprintf(out, "- %s %s\n", change.Node, change.Old)
+		default:
+			fmt.Fprintf(out, "~ %s: %s %s -> %s\n", change.Node, change.Change, orNone(change.Old), orNone(change.New))
+		}
+	}
+	return nil
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// loadDiffNodes loads the module arg with a libsmi of its own and returns its
+// nodes by name.
+func loadDiffNodes(arg string, tempDir string) (map[string]diffNode, error) {
+	gosmi.Init()
+	defer gosmi.Exit()
+
+	setSearchPath(nil)
+
+	module, err := loadModule(arg, tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]diffNode)
+	for _, node := range module.GetNodes() {
+		n := diffNode{oid: node.Oid, kind: node.Kind}
+		if node.Kind&(types.NodeScalar|types.NodeColumn) > 0 {
+			n.access = node.Access
+		}
+		if node.Type != nil {
+			n.typ = node.Type.Name
+			if node.Type.Enum != nil {
+				n.enum = node.Type.Enum.Values
+			}
+		}
+		nodes[node.Name] = n
+	}
+	return nodes, nil
+}
+
+// diffNodes returns the changes from the nodes of oldNodes to those of
+// newNodes, sorted by the OID of the nodes, the new one for nodes that weren't
+// removed.
+func diffNodes(oldNodes map[string]diffNode, newNodes map[string]diffNode) (changes []moduleChange) {
+	for name, oldNode := range oldNodes {
+		if _, ok := newNodes[name]; !ok {
+			changes = append(changes, moduleChange{Node: name, Change: "removed", Old: describeDiffNode(oldNode), oid: oldNode.oid})
+		}
+	}
+
+	for name, newNode := range newNodes {
+		oldNode, ok := oldNodes[name]
+		if !ok {
+			changes = append(changes, moduleChange{Node: name, Change: "added", New: describeDiffNode(newNode), oid: newNode.oid})
+			continue
+		}
+
+		change := func(what string, oldValue string, newValue string) {
+			if oldValue != newValue {
+				changes = append(changes, moduleChange{Node: name, Change: what, Old: oldValue, New: newValue, oid: newNode.oid})
+			}
+		}
+		change("oid", oldNode.oid.String(), newNode.oid.String())
+		change("type", oldNode.typ, newNode.typ)
+		change("access", formatDiffAccess(oldNode.access), formatDiffAccess(newNode.access))
+
+		values := make(map[int64]bool)
+		for value := range oldNode.enum {
+			values[value] = true
+		}
+		for value := range newNode.enum {
+			values[value] = true
+		}
+		sorted := make([]int64, 0, len(values))
+		for value := range values {
+			sorted = append(sorted, value)
+		}
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		for _, value := range sorted {
+			change("enum", formatDiffEnumValue(oldNode.enum, value), formatDiffEnumValue(newNode.enum, value))
+		}
+	}
+
+	// Changes of the same node keep the order they were found in.
+	sort.SliceStable(changes, func(i, j int) bool {
+		if !changes[i].oid.Equals(changes[j].oid) {
+			return oidLess(changes[i].oid, changes[j].oid)
+		}
+		return changes[i].Node < changes[j].Node
+	})
+	return changes
+}
+
+func describeDiffNode(node diffNode) string {
+	description := fmt.Sprintf("%s %s", node.oid, node.kind)
+	if node.typ != "" {
+		description += " " + node.typ
+	}
+	return description
+}
+
+func formatDiffAccess(access types.Access) string {
+	if access == types.AccessUnknown {
+		return ""
+	}
+	return access.String()
+}
+
+func formatDiffEnumValue(values models.EnumValues, value int64) string {
+	label, ok := values[value]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s(%d)", label, value)
+}
+
+func init() {
+	RootCmd.AddCommand(diffCmd)
+
+	flags := diffCmd.Flags()
+	flags.StringSliceVarP(&paths, "path", "M", []string{}, "Path(s) to add to MIB search path")
+	flags.StringSliceVar(&overrides, "override-path", []string{}, "Path(s) searched for MIBs before the default and -M paths, in the order given")
+	flags.StringVar(&diffFormat, "format", "text", "Output format (text or json)")
+}
@@ -0,0 +1,104 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+func TestDiff(t *testing.T) {
+	defer func(format string) { diffFormat = format }(diffFormat)
+
+	oldFile := filepath.Join("..", "testdata", "diff", "old", "FIXTURE-DIFF-MIB")
+	newFile := filepath.Join("..", "testdata", "diff", "new", "FIXTURE-DIFF-MIB")
+	for _, test := range []struct {
+		format string
+		want   string
+	}{
+		{"text", `- fixtureDiffUptime 1.3.6.1.4.1.99999.20.1 Scalar TimeTicks
++ fixtureDiffStatus 1.3.6.1.4.1.99999.20.2.1.3 Column Enumeration
+`},
+		{"json", `[
+  {
+    "node": "fixtureDiffUptime",
+    "change": "removed",
+    "old": "1.3.6.1.4.1.99999.20.1 Scalar TimeTicks"
+  },
+  {
+    "node": "fixtureDiffStatus",
+    "change": "added",
+    "new": "1.3.6.1.4.1.99999.20.2.1.3 Column Enumeration"
+  }
+]
+`},
+	} {
+		diffFormat = test.format
+		out := &bytes.Buffer{}
+		err := diff(oldFile, newFile, out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != test.want {
+			t.Errorf("Unexpected %s output:\n%s\nwant:\n%s", test.format, out, test.want)
+		}
+	}
+
+	diffFormat = "yaml"
+	if err := diff(oldFile, newFile, &bytes.Buffer{}); err == nil {
+		t.Error("Expected an error for an invalid format")
+	}
+}
+
+func TestDiffNodes(t *testing.T) {
+	oid := func(subIDs ...uint32) models.Oid { return append(models.Oid{1, 3, 6, 1, 4, 1, 99999, 20}, subIDs...) }
+	oldNodes := map[string]diffNode{
+		"fixtureDiffUptime": {oid: oid(1), kind: types.NodeScalar, typ: "TimeTicks", access: types.AccessReadOnly},
+		"fixtureDiffName":   {oid: oid(2, 1, 2), kind: types.NodeColumn, typ: "DisplayString", access: types.AccessReadOnly},
+		"fixtureDiffMode":   {oid: oid(2, 1, 4), kind: types.NodeColumn, typ: "INTEGER", access: types.AccessReadOnly, enum: models.EnumValues{1: "a", 2: "b"}},
+	}
+	newNodes := map[string]diffNode{
+		"fixtureDiffName":   {oid: oid(2, 1, 2), kind: types.NodeColumn, typ: "DisplayString", access: types.AccessReadOnly},
+		"fixtureDiffStatus": {oid: oid(2, 1, 3), kind: types.NodeColumn, typ: "INTEGER", access: types.AccessReadOnly},
+		"fixtureDiffMode":   {oid: oid(2, 1, 5), kind: types.NodeColumn, typ: "INTEGER", access: types.AccessReadWrite, enum: models.EnumValues{1: "a", 2: "c", 3: "d"}},
+	}
+
+	var got []string
+	for _, change := range diffNodes(oldNodes, newNodes) {
+		got = append(got, change.Node+" "+change.Change+" "+change.Old+" > "+change.New)
+	}
+	want := []string{
+		"fixtureDiffUptime removed 1.3.6.1.4.1.99999.20.1 Scalar TimeTicks > ",
+		"fixtureDiffStatus added  > 1.3.6.1.4.1.99999.20.2.1.3 Column INTEGER",
+		"fixtureDiffMode oid 1.3.6.1.4.1.99999.20.2.1.4 > 1.3.6.1.4.1.99999.20.2.1.5",
+		"fixtureDiffMode access ReadOnly > ReadWrite",
+		"fixtureDiffMode enum b(2) > c(2)",
+		"fixtureDiffMode enum  > d(3)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected changes:\n%q\nwant:\n%q", got, want)
+	}
+}
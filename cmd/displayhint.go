@@ -0,0 +1,149 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// displayHintDecls interpret the DISPLAY-HINT of octet string types as
+// specified by RFC 2579, section 3.1. They are emitted once into the types
+// file with --display-hint and used by the FormatValue helper of every type.
+const displayHintDecls = `type displayHintSpec struct {
+	repeat     bool
+	length     int
+	format     byte
+	separator  byte
+	terminator byte
+}
+
+func parseDisplayHint(hint string) (specs []displayHintSpec, ok bool) {
+	isDelimiter := func(c byte) bool { return c != '*' && (c < '0' || c > '9') }
+	for i := 0; i < len(hint); {
+		var spec displayHintSpec
+		if hint[i] == '*' {
+			spec.repeat = true
+			i++
+		}
+		start := i
+		for i < len(hint) && hint[i] >= '0' && hint[i] <= '9' {
+			spec.length = spec.length*10 + int(hint[i]-'0')
+			i++
+		}
+		// A length of zero would never consume an octet.
+		if i == start || i == len(hint) || spec.length == 0 {
+			return nil, false
+		}
+		spec.format = hint[i]
+		i++
+		if i < len(hint) && isDelimiter(hint[i]) {
+			spec.separator = hint[i]
+			i++
+		}
+		if spec.repeat && i < len(hint) && isDelimiter(hint[i]) {
+			spec.terminator = hint[i]
+			i++
+		}
+		specs = append(specs, spec)
+	}
+	return specs, len(specs) > 0
+}
+
+// formatDisplayHint renders raw according to the octet string DISPLAY-HINT
+// hint. The last part of a hint is repeated until raw is exhausted. Values
+// that don't fit a hint are rendered in hexadecimal.
+func formatDisplayHint(hint string, raw []byte) string {
+	specs, ok := parseDisplayHint(hint)
+	if !ok {
+		return fmt.Sprintf("%x", raw)
+	}
+
+	var b strings.Builder
+	for s, pos := 0, 0; pos < len(raw); {
+		spec := specs[s]
+		count := 1
+		if spec.repeat {
+			count = int(raw[pos])
+			pos++
+		}
+		for c := 0; c < count && pos < len(raw); c++ {
+			n := spec.length
+			if n > len(raw)-pos {
+				n = len(raw) - pos
+			}
+			chunk := raw[pos : pos+n]
+			pos += n
+
+			var value uint64
+			for _, octet := range chunk {
+				value = value<<8 | uint64(octet)
+			}
+			switch spec.format {
+			case 'x':
+				for _, octet := range chunk {
+					fmt.Fprintf(&b, "%02x", octet)
+				}
+			case 'd':
+				fmt.Fprintf(&b, "%d", value)
+			case 'o':
+				fmt.Fprintf(&b, "%o", value)
+			case 'a', 't':
+				b.Write(chunk)
+			default:
+				return fmt.Sprintf("%x", raw)
+			}
+
+			if pos >= len(raw) {
+				break
+			}
+			if spec.repeat && c == count-1 && spec.terminator != 0 {
+				b.WriteByte(spec.terminator)
+			} else if spec.separator != 0 {
+				b.WriteByte(spec.separator)
+			}
+		}
+		if s < len(specs)-1 {
+			s++
+		}
+	}
+	return b.String()
+}
+
+`
+
+// hasDisplayHint reports whether t is an octet string type with a
+// DISPLAY-HINT, which is all formatDisplayHint interprets.
+func hasDisplayHint(t *models.Type) bool {
+	return t != nil && t.BaseType == types.BaseTypeOctetString && t.Format != ""
+}
+
+// generateFormatValue emits a helper rendering raw values of a type, named
+// after typeName, according to its DISPLAY-HINT.
+func generateFormatValue(buf io.Writer, typeName string, t *models.Type) {
+	fmt.Fprintf(buf, "// %sFormatValue renders raw according to the DISPLAY-HINT %q.\n", typeName, t.Format)
+	fmt.Fprintf(buf, "func %sFormatValue(raw []byte) string {\n", typeName)
+	fmt.Fprintf(buf, "\treturn formatDisplayHint(%q, raw)\n", t.Format)
+	fmt.Fprintf(buf, "}\n\n")
+}
@@ -0,0 +1,39 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import "testing"
+
+func TestParseDisplayHint(t *testing.T) {
+	main := `func main() {
+	for _, hint := range []string{"255a", "1x:", "*1d.", "0a", "1x:0d", "a", "1"} {
+		_, ok := parseDisplayHint(hint)
+		fmt.Println(hint, ok)
+	}
+	fmt.Println(formatDisplayHint("0a", []byte("ab")))
+}
+`
+	got := runGenerated(t, imports{"strings": true}, displayHintDecls, main)
+	want := "255a true\n1x: true\n*1d. true\n0a false\n1x:0d false\na false\n1 false\n6162"
+	if got != want {
+		t.Errorf("Got\n%s\nexpected\n%s", got, want)
+	}
+}
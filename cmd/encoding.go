@@ -0,0 +1,94 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	bomUTF8    = []byte{0xef, 0xbb, 0xbf}
+	bomUTF16BE = []byte{0xfe, 0xff}
+	bomUTF16LE = []byte{0xff, 0xfe}
+)
+
+// normalizeMibFile prepares the MIB file at filename for libsmi, which neither
+// skips byte order marks nor knows about encodings. If the file needs to be
+// changed, a normalized copy with the same base name is written to tempDir and
+// its path returned, otherwise filename is returned as is.
+func normalizeMibFile(filename string, tempDir string) (string, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", errors.Wrapf(err, "Reading file %s", filename)
+	}
+
+	normalized := normalizeEncoding(b)
+	if bytes.Equal(normalized, b) {
+		return filename, nil
+	}
+
+	normalizedFilename := filepath.Join(tempDir, filepath.Base(filename))
+	err = ioutil.WriteFile(normalizedFilename, normalized, 0644)
+	if err != nil {
+		return "", errors.Wrapf(err, "Writing file %s", normalizedFilename)
+	}
+
+	return normalizedFilename, nil
+}
+
+// normalizeEncoding returns b as UTF-8 without a byte order mark. UTF-16 is
+// only recognized by its byte order mark, and anything else that is not valid
+// UTF-8 is taken to be Latin-1, which is what vendor MIBs use in practice.
+func normalizeEncoding(b []byte) []byte {
+	switch {
+	case bytes.HasPrefix(b, bomUTF8):
+		b = b[len(bomUTF8):]
+	case bytes.HasPrefix(b, bomUTF16BE):
+		return decodeUTF16(b[len(bomUTF16BE):], binary.BigEndian)
+	case bytes.HasPrefix(b, bomUTF16LE):
+		return decodeUTF16(b[len(bomUTF16LE):], binary.LittleEndian)
+	}
+
+	if utf8.Valid(b) {
+		return b
+	}
+
+	buf := &bytes.Buffer{}
+	for _, c := range b {
+		buf.WriteRune(rune(c))
+	}
+	return buf.Bytes()
+}
+
+func decodeUTF16(b []byte, order binary.ByteOrder) []byte {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = order.Uint16(b[2*i:])
+	}
+	return []byte(string(utf16.Decode(units)))
+}
@@ -0,0 +1,99 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeEncoding(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		b    string
+		want string
+	}{
+		{"UTF-8", "Temperature in °C", "Temperature in °C"},
+		{"UTF-8 BOM", "\xef\xbb\xbfTemperature in °C", "Temperature in °C"},
+		{"UTF-16BE BOM", "\xfe\xff\x00\xb0\x00C", "°C"},
+		{"UTF-16LE BOM", "\xff\xfe\xb0\x00C\x00", "°C"},
+		{"Latin-1", "Temperature in \xb0C", "Temperature in °C"},
+		{"empty", "", ""},
+	} {
+		if got := string(normalizeEncoding([]byte(test.b))); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestNormalizeMibFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join("..", "testdata", "access", "FIXTURE-ACCESS-MIB")
+	normalized, err := normalizeMibFile(filename, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normalized != filename {
+		t.Errorf("Expected %s to be loaded as is, got %s", filename, normalized)
+	}
+
+	filename = filepath.Join("..", "testdata", "bom", "FIXTURE-BOM-MIB")
+	normalized, err = normalizeMibFile(filename, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normalized != filepath.Join(dir, "FIXTURE-BOM-MIB") {
+		t.Fatalf("Expected a normalized copy of %s in %s, got %s", filename, dir, normalized)
+	}
+	b, err := ioutil.ReadFile(normalized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b[:2]) != "--" {
+		t.Errorf("Expected the byte order mark to be stripped, got %q", b[:2])
+	}
+}
+
+func TestBOM(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	moduleList := filepath.Join(dir, "modules")
+	err = ioutil.WriteFile(moduleList, []byte(filepath.Join("..", "testdata", "bom", "FIXTURE-BOM-MIB")+"\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = generateSources(nil, []string{"--dir", dir, "--from-file", moduleList})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, readGenerated(t, dir), "The temperature in °C.")
+}
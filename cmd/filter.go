@@ -0,0 +1,135 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// filtered holds the nodes kept by --only and --exclude, the nodes selected
+// and their dependencies, or is nil if neither is given.
+var filtered map[nodeKey]bool
+
+// namePattern matches node names for --only and --exclude. Patterns enclosed
+// in slashes are regular expressions, which match if they match any part of a
+// name, the others are globs, which have to match all of it.
+type namePattern struct {
+	glob string
+	re   *regexp.Regexp
+}
+
+func parseNamePatterns(patterns []string) ([]namePattern, error) {
+	parsed := make([]namePattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "Invalid node name pattern %s", pattern)
+			}
+			parsed = append(parsed, namePattern{re: re})
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "Invalid node name pattern %s", pattern)
+		}
+		parsed = append(parsed, namePattern{glob: pattern})
+	}
+	return parsed, nil
+}
+
+func matchesAny(patterns []namePattern, name string) bool {
+	for _, pattern := range patterns {
+		if pattern.re != nil {
+			if pattern.re.MatchString(name) {
+				return true
+			}
+		} else if matched, _ := path.Match(pattern.glob, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// selectNodes returns the nodes of modules whose names match only, or any name
+// if only is empty, and don't match exclude.
+func selectNodes(modules []gosmi.SmiModule, only []namePattern, exclude []namePattern) (selected []gosmi.SmiNode) {
+	for _, module := range modules {
+		for _, node := range module.GetNodes() {
+			if (len(only) == 0 || matchesAny(only, node.Name)) && !matchesAny(exclude, node.Name) {
+				selected = append(selected, node)
+			}
+		}
+	}
+	return selected
+}
+
+// dependencyClosure returns the nodes selected along with the nodes their vars
+// reference, directly or not, which are kept even if excluded, so every
+// reference resolves. The shared types are those of the nodes kept, as they
+// are collected from the nodes generated.
+func dependencyClosure(selected []gosmi.SmiNode) map[nodeKey]bool {
+	kept := make(map[nodeKey]bool)
+	pending := append([]gosmi.SmiNode(nil), selected...)
+	for len(pending) > 0 {
+		node := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		key := nodeKey{node.GetModule().Name, node.Name}
+		if kept[key] || statusSkipped(node) {
+			continue
+		}
+		kept[key] = true
+		pending = append(pending, nodeDependencies(node)...)
+	}
+	return kept
+}
+
+// nodeDependencies returns the nodes the generated var of node references.
+func nodeDependencies(node gosmi.SmiNode) (deps []gosmi.SmiNode) {
+	switch node.Kind {
+	case types.NodeTable:
+		deps = append(deps, node.GetRow())
+	case types.NodeRow:
+		columns, columnOrder := node.GetColumns()
+		for _, column := range columnOrder {
+			deps = append(deps, columns[column])
+		}
+		index, base, inherited := rowIndex(node)
+		deps = append(deps, index...)
+		if inherited {
+			deps = append(deps, base)
+		}
+	case types.NodeNotification, types.NodeGroup:
+		deps = append(deps, node.GetNotificationObjects()...)
+	case types.NodeCompliance:
+		deps = append(deps, node.GetNotificationObjects()...)
+		deps = append(deps, complianceOptions(node)...)
+		for _, refinement := range complianceRefinements(node) {
+			deps = append(deps, refinement.object)
+		}
+	}
+	return deps
+}
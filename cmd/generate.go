@@ -21,262 +21,3112 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"go/ast"
+	"go/build/constraint"
 	"go/format"
+	"go/parser"
+	"go/token"
 	"io"
+	"io/ioutil"
 	"log"
+	"math"
 	"os"
 	"path"
+	"path/filepath"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 	"github.com/sleepinggenius2/gosmi"
 	"github.com/sleepinggenius2/gosmi/models"
 	"github.com/sleepinggenius2/gosmi/types"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
-const fileHeader = `// Code generated by mib2go. DO NOT EDIT.
-package %s
+const (
+	modelsImport = "github.com/sleepinggenius2/gosmi/models"
+	typesImport  = "github.com/sleepinggenius2/gosmi/types"
+)
 
-import (
-	"github.com/sleepinggenius2/gosmi/models"
-	"github.com/sleepinggenius2/gosmi/types"
+const cellOidDecls = `// EncodeIndex encodes the values of the index columns of a row into the suffix
+// of the OIDs of its cells. Integers are encoded as a single sub-identifier,
+// octet strings, given as a string or []byte, and object identifiers as their
+// length followed by a sub-identifier per octet or sub-identifier. The length
+// is left out for fixed-size octet strings and the last column of an IMPLIED
+// index.
+func EncodeIndex(index []models.ColumnNode, implied bool, values ...interface{}) (models.Oid, error) {
+	if len(values) != len(index) {
+		return nil, fmt.Errorf("Got %d index values for %d index columns", len(values), len(index))
+	}
+
+	var suffix models.Oid
+	for i, value := range values {
+		var subIDs models.Oid
+		v := reflect.ValueOf(value)
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if v.Int() < 0 || v.Int() > 1<<32-1 {
+				return nil, fmt.Errorf("Index value %d of %s out of range", v.Int(), index[i].Name)
+			}
+			suffix = append(suffix, uint32(v.Int()))
+			continue
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if v.Uint() > 1<<32-1 {
+				return nil, fmt.Errorf("Index value %d of %s out of range", v.Uint(), index[i].Name)
+			}
+			suffix = append(suffix, uint32(v.Uint()))
+			continue
+		case reflect.String:
+			for _, b := range []byte(v.String()) {
+				subIDs = append(subIDs, uint32(b))
+			}
+		case reflect.Slice:
+			for j := 0; j < v.Len(); j++ {
+				switch element := v.Index(j); element.Kind() {
+				case reflect.Uint8, reflect.Uint32:
+					subIDs = append(subIDs, uint32(element.Uint()))
+				default:
+					return nil, fmt.Errorf("Index value %v of %s is neither an octet string nor an OID", value, index[i].Name)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("Index value %v of %s is neither an integer, an octet string nor an OID", value, index[i].Name)
+		}
+		if !(implied && i == len(index)-1) && !fixedSize(index[i].Type) {
+			suffix = append(suffix, uint32(len(subIDs)))
+		}
+		suffix = append(suffix, subIDs...)
+	}
+	return suffix, nil
+}
+
+func fixedSize(t models.Type) bool {
+	return t.BaseType == types.BaseTypeOctetString && len(t.Ranges) == 1 && t.Ranges[0].MinValue == t.Ranges[0].MaxValue
+}
+
+`
+const baseNodeAssertions = `// Compile-time checks that the node models of the models package still have
+// the shape the generated code relies on.
+var (
+	_ models.Oid = models.BaseNode{}.Oid
+	_ string     = models.BaseNode{}.OidFormatted
+	_ string     = models.BaseNode{}.Name
+)
+
+`
+
+// modelsAssertions are the compile-time checks of the fields of the model of
+// each kind of node that the generated code sets, emitted with --assert-models
+// for the kinds of nodes that are generated.
+var modelsAssertions = []struct {
+	kind       types.NodeKind
+	assertions string
+}{
+	{types.NodeScalar, `var (
+	_ models.BaseNode = models.ScalarNode{}.BaseNode
+	_ models.Type     = models.ScalarNode{}.Type
+)
+
+`},
+	{types.NodeTable, `var (
+	_ models.BaseNode = models.TableNode{}.BaseNode
+	_ models.RowNode  = models.TableNode{}.Row
+)
+
+`},
+	{types.NodeRow, `var (
+	_ models.BaseNode     = models.RowNode{}.BaseNode
+	_ []models.ColumnNode = models.RowNode{}.Columns
+	_ []models.ColumnNode = models.RowNode{}.Index
+	_ bool                = models.RowNode{}.Implied
+)
+
+`},
+	{types.NodeColumn, `var (
+	_ models.ScalarNode = models.ColumnNode{}.ScalarNode
+	_ models.BaseNode   = models.ColumnNode{}.BaseNode
+	_ models.Type       = models.ColumnNode{}.Type
 )
 
+`},
+	{types.NodeNotification, `var (
+	_ models.BaseNode     = models.NotificationNode{}.BaseNode
+	_ []models.ScalarNode = models.NotificationNode{}.Objects
+)
+
+`},
+}
+
+const registryDecls = `// Registry holds the modules registered by the init functions of this
+// package, keyed by module name.
+var Registry = map[string]interface{}{}
+
+// Register adds module to the Registry. Registering a module again replaces
+// the earlier entry, so registration is idempotent.
+func Register(name string, module interface{}) {
+	Registry[name] = module
+}
+
+`
+const indexCodecDecls = `// EncodeIndexOctets appends the octets of s to oid as an index value,
+// preceded by their number with withLength.
+func EncodeIndexOctets(oid models.Oid, s string, withLength bool) models.Oid {
+	if withLength {
+		oid = append(oid, uint32(len(s)))
+	}
+	for i := 0; i < len(s); i++ {
+		oid = append(oid, uint32(s[i]))
+	}
+	return oid
+}
+
+// EncodeIndexOid appends the sub-identifiers of the OID in dotted notation to
+// oid as an index value, preceded by their number with withLength.
+func EncodeIndexOid(oid models.Oid, dotted string, withLength bool) (models.Oid, error) {
+	var subIDs models.Oid
+	if dotted != "" {
+		for _, part := range strings.Split(dotted, ".") {
+			subID, err := strconv.ParseUint(part, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("Parsing index OID %s: %v", dotted, err)
+			}
+			subIDs = append(subIDs, uint32(subID))
+		}
+	}
+	if withLength {
+		oid = append(oid, uint32(len(subIDs)))
+	}
+	return append(oid, subIDs...), nil
+}
+
+// DecodeIndexValue splits the sub-identifiers of the next index value off
+// suffix, which are size many, or as many as the first one gives, which is
+// left out of value, if size is negative.
+func DecodeIndexValue(suffix models.Oid, size int) (value models.Oid, rest models.Oid, err error) {
+	if size < 0 {
+		if len(suffix) == 0 {
+			return nil, nil, fmt.Errorf("Index value lacks its length")
+		}
+		size, suffix = int(suffix[0]), suffix[1:]
+	}
+	if size > len(suffix) {
+		return nil, nil, fmt.Errorf("Index value needs %d sub-identifiers, only %d left", size, len(suffix))
+	}
+	return suffix[:size], suffix[size:], nil
+}
+
+// DecodeIndexOctets returns the octet string of the index value.
+func DecodeIndexOctets(value models.Oid) (string, error) {
+	b := make([]byte, len(value))
+	for i, subID := range value {
+		if subID > 255 {
+			return "", fmt.Errorf("Index value %s is no octet string", value)
+		}
+		b[i] = byte(subID)
+	}
+	return string(b), nil
+}
+
+`
+const oidRangeDecls = `// OidRange is the range of OIDs a walk of a table covers, from Start up to but
+// excluding End.
+type OidRange struct {
+	Start models.Oid
+	End   models.Oid
+}
+
+// Contains reports whether oid lies within r.
+func (r OidRange) Contains(oid models.Oid) bool {
+	return compareOids(oid, r.Start) >= 0 && (r.End == nil || compareOids(oid, r.End) < 0)
+}
+
+func compareOids(a models.Oid, b models.Oid) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+`
+const notificationDecoder = `// RawVarbind is a varbind as received, independent of the SNMP library that
+// received it.
+type RawVarbind struct {
+	Oid   string
+	Value interface{}
+}
+
+// UnknownNotificationError is returned by DecodeNotification for an OID that is
+// not the OID of any notification in this package.
+type UnknownNotificationError string
+
+func (e UnknownNotificationError) Error() string {
+	return "Unknown notification " + string(e)
+}
+
+// DecodeNotification maps the varbinds of the notification with the given OID
+// to the objects it declares, keyed by object name. Integer values of
+// enumerated objects are decoded to their labels, other values are kept as is.
+// Varbinds that match none of the objects, like sysUpTime.0, are skipped.
+func DecodeNotification(oid string, varbinds []RawVarbind) (map[string]interface{}, error) {
+	notification, ok := notifications[trimOidDot(oid)]
+	if !ok {
+		return nil, UnknownNotificationError(oid)
+	}
+
+	decoded := make(map[string]interface{}, len(notification.Objects))
+	for _, varbind := range varbinds {
+		varbindOid := trimOidDot(varbind.Oid)
+		for _, object := range notification.Objects {
+			objectOid := object.Oid.String()
+			if varbindOid != objectOid && (len(varbindOid) <= len(objectOid) || varbindOid[:len(objectOid)+1] != objectOid+".") {
+				continue
+			}
+			decoded[object.Name] = varbind.Value
+			if label, ok := enumLabel(object.Type, varbind.Value); ok {
+				decoded[object.Name] = label
+			}
+			break
+		}
+	}
+	return decoded, nil
+}
+
+func trimOidDot(oid string) string {
+	if len(oid) > 0 && oid[0] == '.' {
+		return oid[1:]
+	}
+	return oid
+}
+
+func enumLabel(t models.Type, value interface{}) (string, bool) {
+	if t.Enum == nil {
+		return "", false
+	}
+	var key int64
+	switch v := value.(type) {
+	case int:
+		key = int64(v)
+	case int32:
+		key = int64(v)
+	case int64:
+		key = v
+	case uint:
+		key = int64(v)
+	case uint32:
+		key = int64(v)
+	case uint64:
+		key = int64(v)
+	default:
+		return "", false
+	}
+	label, ok := t.Enum.Values[key]
+	return label, ok
+}
+
+`
+const resolveDecls = `type oidTrieNode struct {
+	children map[uint32]*oidTrieNode
+	node     *models.BaseNode
+}
+
+var oidTrie = buildOidTrie(resolvableNodes)
+
+func buildOidTrie(nodes []models.BaseNode) *oidTrieNode {
+	root := &oidTrieNode{}
+	for i := range nodes {
+		current := root
+		for _, subID := range nodes[i].Oid {
+			if current.children == nil {
+				current.children = make(map[uint32]*oidTrieNode)
+			}
+			next, ok := current.children[subID]
+			if !ok {
+				next = &oidTrieNode{}
+				current.children[subID] = next
+			}
+			current = next
+		}
+		current.node = &nodes[i]
+	}
+	return root
+}
+
+// Resolve returns the scalar or column node with the longest OID that is a
+// prefix of oid, along with the rest of oid following it, which is the index
+// of a column. The index shares its elements with oid.
+func Resolve(oid []uint32) (node models.BaseNode, index []uint32, ok bool) {
+	current := oidTrie
+	for i, subID := range oid {
+		if current.node != nil {
+			node, index, ok = *current.node, oid[i:], true
+		}
+		current = current.children[subID]
+		if current == nil {
+			return
+		}
+	}
+	if current.node != nil {
+		node, index, ok = *current.node, oid[len(oid):], true
+	}
+	return
+}
+
+`
+const moduleInfoDecls = `// ModuleInfo holds the metadata of a module. LastUpdated is the date of its
+// latest revision.
+type ModuleInfo struct {
+	LastUpdated  time.Time
+	Organization string
+	ContactInfo  string
+	Revisions    []models.Revision
+}
+
+`
+
+// undatedModuleInfoDecls take the place of moduleInfoDecls if none of the
+// modules has a revision, so the types file doesn't import time just for
+// LastUpdated.
+const undatedModuleInfoDecls = `// ModuleInfo holds the metadata of a module.
+type ModuleInfo struct {
+	Organization string
+	ContactInfo  string
+	Revisions    []models.Revision
+}
+
+`
+const groupDecls = `// GroupNode is an OBJECT-GROUP or NOTIFICATION-GROUP with its members.
+type GroupNode struct {
+	models.BaseNode
+	ID      string
+	Status  types.Status
+	Objects []models.BaseNode
+}
+
+`
+const nodeInfoDecls = `// NodeInfo holds what a MIB declares about a node that the gosmi model of the
+// node has no field for.
+type NodeInfo struct {
+	ID           string
+	Status       types.Status
+	Syntax       string
+	Access       types.Access
+	Default      interface{}
+	Augments     models.BaseNode
+	NotifyOnly   []models.ScalarNode
+	Enterprise   models.Oid
+	SpecificTrap uint32
+}
+
 `
-const allowedNodeKinds = types.NodeScalar | types.NodeTable | types.NodeRow | types.NodeColumn | types.NodeNotification
+const allowedNodeKinds = types.NodeScalar | types.NodeTable | types.NodeRow | types.NodeColumn | types.NodeNotification | types.NodeGroup | types.NodeCompliance
 
 var (
 	outDir      string
 	outFilename string
 	packageName string
 	paths       []string
+	overrides   []string
+
+	formatChunkSize   int
+	emitSyntax        bool
+	emitLanguage      bool
+	emitNodeInfo      bool
+	emitModuleOid     bool
+	emitModuleInfo    bool
+	emitNodeLookup    bool
+	emitKindAccessors bool
+	emitEnterprise    bool
+	unexportedVars    bool
+	emitTablesMap     bool
+	oidType           string
+	qualifyDuplicates bool
+	emitEnumLabels    bool
+	finalNewline      bool
+	emitCellOid       bool
+	emitAssertions    bool
+	emitRegister      bool
+	emitTableRanges   bool
+	emitNotifyDecoder bool
+	emitNodeIDs       bool
+	ownOnly           bool
+	verifyOids        bool
+	standalone        bool
+	emitOidArrays     bool
+	descriptionsFile  bool
+	noDescriptions    bool
+	commentWidth      int
+	fromFile          string
+	loadDir           string
+	withImports       bool
+	jobs              int
+	dryRun            bool
+	keepGoing         bool
+	emitModulesMap    bool
+	trapDecoders      bool
+	unitWrappers      bool
+	rangeValidators   bool
+	stdoutSplit       bool
+	enumStrings       bool
+	enumParse         bool
+	enumParseCI       bool
+	flattenTables     bool
+	preserveAcronyms  bool
+	acronyms          []string
+	renameFile        string
+	oidComments       bool
+	onlyNodes         []string
+	excludeNodes      []string
+	smiTypes          bool
+	emitIndexStructs  bool
+	verifyRoundTrips  bool
+	lazyNodes         bool
+	canonical         bool
+	nativeTypes       bool
+	emitEnumConsts    bool
+	emitOidIndex      bool
+	emitResolve       bool
+	outputFormat      string
+	skipObsolete      bool
+	skipDeprecated    bool
+	displayHints      bool
+	subpackages       bool
+	importPath        string
+	typeConflicts     string
+	resolveImports    bool
+	buildTag          string
+	conformance       bool
+	headerFile        string
+
+	// headerTemplate is the header loaded from --header-file, if any.
+	headerTemplate *template.Template
+
+	// commentReplacers keep descriptions from ending or seemingly nesting the
+	// comments they are put in. They are applied in turn, as a single pass
+	// would turn "/*/" into "/ */".
+	commentReplacers = []*strings.Replacer{
+		strings.NewReplacer("*/", "* /"),
+		strings.NewReplacer("/*", "/ *"),
+	}
+
+	// smiLanguageModules define the SMI itself rather than objects, and are
+	// never added by --with-imports.
+	smiLanguageModules = map[string]bool{
+		"RFC1065-SMI": true,
+		"RFC1155-SMI": true,
+		"RFC-1212":    true,
+		"RFC-1215":    true,
+		"SNMPv2-SMI":  true,
+		"SNMPv2-TC":   true,
+		"SNMPv2-CONF": true,
+	}
+
+	// inlineTypeNames are the names libsmi gives to the base types and their
+	// anonymous refinements, which are generated inline instead of shared.
+	inlineTypeNames = map[string]bool{
+		"Integer32":        true,
+		"OctetString":      true,
+		"ObjectIdentifier": true,
+		"Unsigned32":       true,
+		"Integer64":        true,
+		"Unsigned64":       true,
+		"Enumeration":      true,
+		"Bits":             true,
+	}
+
+	// oidElementTypes are the element types OIDs can additionally be emitted
+	// with, besides the models.Oid every node carries. gosnmp emits uint32
+	// along with the dotted string gosnmp uses, with a leading dot.
+	oidElementTypes = map[string]bool{
+		"models": true,
+		"gosnmp": true,
+		"int":    true,
+		"int64":  true,
+		"uint":   true,
+		"uint32": true,
+		"uint64": true,
+	}
+
+	// predeclaredIdentifiers are the identifiers of the Go universe block.
+	predeclaredIdentifiers = map[string]bool{
+		"any": true, "bool": true, "byte": true, "comparable": true,
+		"complex64": true, "complex128": true, "error": true, "float32": true,
+		"float64": true, "int": true, "int8": true, "int16": true,
+		"int32": true, "int64": true, "rune": true, "string": true,
+		"uint": true, "uint8": true, "uint16": true, "uint32": true,
+		"uint64": true, "uintptr": true, "true": true, "false": true,
+		"iota": true, "nil": true, "append": true, "cap": true,
+		"clear": true, "close": true, "complex": true, "copy": true,
+		"delete": true, "imag": true, "len": true, "make": true,
+		"max": true, "min": true, "new": true, "panic": true,
+		"print": true, "println": true, "real": true, "recover": true,
+	}
+
+	// nativeFieldTypes are the Go types values of each base type are held in
+	// with --native-types. Object identifiers are kept as their sub-identifiers
+	// and BITS as the octets they are encoded in. Types not listed here are
+	// held as raw octets.
+	nativeFieldTypes = map[types.BaseType]string{
+		types.BaseTypeInteger32:        "int32",
+		types.BaseTypeOctetString:      "[]byte",
+		types.BaseTypeObjectIdentifier: "[]uint32",
+		types.BaseTypeUnsigned32:       "uint32",
+		types.BaseTypeInteger64:        "int64",
+		types.BaseTypeUnsigned64:       "uint64",
+		types.BaseTypeFloat32:          "float32",
+		types.BaseTypeFloat64:          "float64",
+		types.BaseTypeEnum:             "int32",
+		types.BaseTypeBits:             "[]byte",
+	}
+
+	// indexFieldTypes are the Go types of index struct fields by base type.
+	// Octet strings become strings rather than byte slices, which keeps the
+	// structs comparable and usable as map keys.
+	indexFieldTypes = map[types.BaseType]string{
+		types.BaseTypeInteger32:        "int32",
+		types.BaseTypeOctetString:      "string",
+		types.BaseTypeObjectIdentifier: "string",
+		types.BaseTypeUnsigned32:       "uint32",
+		types.BaseTypeInteger64:        "int64",
+		types.BaseTypeUnsigned64:       "uint64",
+		types.BaseTypeEnum:             "int32",
+		types.BaseTypeBits:             "string",
+	}
 
-	commentReplacer = strings.NewReplacer("*/", "* /")
+	syntaxKeywords = map[types.BaseType]string{
+		types.BaseTypeInteger32:        "INTEGER",
+		types.BaseTypeOctetString:      "OCTET STRING",
+		types.BaseTypeObjectIdentifier: "OBJECT IDENTIFIER",
+		types.BaseTypeUnsigned32:       "Unsigned32",
+		types.BaseTypeInteger64:        "Integer64",
+		types.BaseTypeUnsigned64:       "Unsigned64",
+		types.BaseTypeEnum:             "INTEGER",
+		types.BaseTypeBits:             "BITS",
+	}
 )
 
+// sharedDecls collects what the modules of a package have in common, which is
+// generated once into the types file.
+type sharedDecls struct {
+	// types are the shared types by name, each emitted once into the types
+	// file. They are all decided by resolveTypeNames before generating, so
+	// they don't depend on the order modules are generated in.
+	types         map[string]*models.Type
+	tables        []gosmi.SmiNode
+	notifications []gosmi.SmiNode
+	varNames      map[nodeKey]string
+	typeNames     map[nodeKey]string
+	generated     map[string]bool
+	external      map[nodeKey]gosmi.SmiNode
+	descriptions  map[string]string
+	oidIndex      map[string]string
+	resolvable    []string
+	groups        bool
+	lastUpdated   bool
+	nodeInfo      bool
+	trapDecoders  bool
+	compliances   bool
+	timeTicks     bool
+
+	// nodeKinds are the kinds of the nodes generated so far.
+	nodeKinds types.NodeKind
+
+	// module is the name of the module being generated.
+	module string
+}
+
+type nodeKey struct {
+	module string
+	name   string
+}
+
 // generateCmd represents the generate command
 var generateCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Generates Go files from MIBs",
-	Long:  `Generates Go files from MIBs.`,
-	Args:  cobra.MinimumNArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) (err error) {
-		gosmi.Init()
-		defer gosmi.Exit()
-
-		for _, path := range paths {
-			gosmi.AppendPath(path)
-		}
-
-		var out *os.File
-		if outFilename == "-" {
-			out = os.Stdout
-		} else if outFilename != "" {
-			var err error
-			out, err = os.OpenFile(outFilename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	Long: `Generates Go files from MIBs.
+
+When writing to a single output with -o, modules are separated by a blank line
+and the output ends with exactly one newline, which --final-newline=false drops
+when writing to stdout. Every file gets exactly one generated-code header, and
+the //go:generate directives above the package clause of a file being
+regenerated are kept, so mib2go can be run from a directive in its own output.
+
+MIBs are searched for in the --override-path paths, then the default libsmi
+path, then the -M paths, each in the order given. To have vendor definitions win
+over a module that also exists in a standard location, pass the vendor directory
+with --override-path. The path each module was loaded from is logged. An
+argument is only loaded as a file rather than searched for as a module if it
+contains a path separator or ends in .mib, .my, .txt or .smi, e.g. ./IF-MIB.
+
+With --standalone, the generated code doesn't depend on gosmi. The types file
+then declares Oid, BaseType, Language, Access, Status, Range, EnumValues, Enum,
+Type, Revision and the BaseNode, ScalarNode, ColumnNode, RowNode, TableNode and
+NotificationNode structs itself, with the fields and constants of the same
+names in gosmi that the generated code uses.
+
+The DEFVAL of scalars and columns is generated as the Default of their
+NodeInfo.
+
+With --canonical, the output is put into a form suited for reviewing changes:
+modules are generated in order of their name rather than the order given,
+descriptions have their line endings normalized, trailing whitespace stripped
+and leading and trailing blank lines dropped, and the output always ends with a
+newline. Nodes are kept in the order of their module, and shared types, maps
+and enumerations are always sorted.
+
+With --subpackages, every module is generated into a package of its own, named
+after the module in lower case without hyphens, in a directory of that name
+below -d, e.g. IF-MIB into ifmib/ifmib.go. The shared types and declarations
+are generated into the mibtypes subpackage instead of types.go. Subpackages are
+imported by the import path of -d given with --import-path. Nodes of other
+modules are referenced through their module var, e.g. ifmib.IfMib.IfIndex, so
+modules referencing each other can't be generated into subpackages.
+
+Any flag can also be set in the generate section of the config file, using the
+long flag name as key. Flags given on the command line take precedence over the
+config file, which takes precedence over the defaults.
+
+With --from-file, the modules listed in a file, one per line, are generated in
+addition to those given as arguments. Blank lines and everything after a # are
+ignored. Modules given more than once are only generated once.
+
+With --load-dir, all modules defined in the files below a directory are
+generated as well. Files are recognized by their module definitions, whatever
+their name, and loaded in the order of their imports.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := applyConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		return generate(args)
+	},
+}
+
+// generate generates Go code for the modules given by name or path in args.
+// searchPaths are searched for MIBs before any other path.
+func generate(args []string, searchPaths ...string) (err error) {
+	if fromFile != "" {
+		listed, err := readModuleList(fromFile)
+		if err != nil {
+			return err
+		}
+		args = append(append([]string(nil), args...), listed...)
+	}
+	if loadDir != "" {
+		found, err := loadDirArgs(loadDir)
+		if err != nil {
+			return err
+		}
+		args = append(append([]string(nil), args...), found...)
+	}
+	args = uniqueArgs(args)
+	if len(args) == 0 {
+		return errors.New("No modules given")
+	}
+	if canonical {
+		finalNewline = true
+	}
+	if outputFormat != "go" && outputFormat != "json" {
+		return errors.Errorf("Invalid format %s", outputFormat)
+	}
+	if typeConflicts != "error" && typeConflicts != "rename" && typeConflicts != "first" {
+		return errors.Errorf("Invalid type conflict handling %s", typeConflicts)
+	}
+	if buildTag != "" {
+		if _, err := constraint.Parse("//go:build " + buildTag); err != nil {
+			return errors.Wrapf(err, "Invalid build tag %s", buildTag)
+		}
+	}
+	renames, renamed = nil, make(map[string]bool)
+	if renameFile != "" {
+		renames, err = readRenameFile(renameFile)
+		if err != nil {
+			return err
+		}
+	}
+	headerTemplate = nil
+	if headerFile != "" {
+		headerTemplate, err = loadHeaderTemplate(headerFile)
+		if err != nil {
+			return err
+		}
+	}
+	if !oidElementTypes[oidType] {
+		return errors.Errorf("Invalid OID type %s", oidType)
+	}
+	if jobs < 1 {
+		return errors.Errorf("Invalid number of jobs %d", jobs)
+	}
+	if commentWidth < 0 {
+		return errors.Errorf("Invalid comment width %d", commentWidth)
+	}
+	if standalone && (emitCellOid || emitAssertions) {
+		return errors.New("--cell-oid and --assert-models rely on the models package and can't be used with --standalone")
+	}
+	if enumParseCI && !enumParse {
+		return errors.New("--enum-parse-ci needs --enum-parse")
+	}
+	if noDescriptions && descriptionsFile {
+		return errors.New("--no-descriptions and --descriptions-file can't be used together")
+	}
+	if subpackages {
+		if outFilename != "" || importPath == "" {
+			return errors.New("--subpackages needs -d instead of -o and the import path of -d given with --import-path")
+		}
+		if standalone || ownOnly || unexportedVars || emitTablesMap || emitNotifyDecoder || emitResolve || emitOidIndex || displayHints || emitModulesMap || trapDecoders || descriptionsFile {
+			return errors.New("--standalone, --own-only, --unexported, --tables-map, --notification-decoder, --resolve, --oid-index, --display-hint, --modules-map, --trap-decoders and --descriptions-file rely on a single package and can't be used with --subpackages")
+		}
+	}
+
+	// Registered first, so it runs last and the modules that didn't fail
+	// are written before the failures are reported.
+	var failures moduleErrors
+	defer func() {
+		if err == nil && len(failures) > 0 {
+			err = failures
+		}
+	}()
+
+	gosmi.Init()
+	defer gosmi.Exit()
+
+	setSearchPath(searchPaths)
+
+	var out io.Writer
+	if outFilename == "-" {
+		out = os.Stdout
+		if !finalNewline {
+			out = &trailingNewlineWriter{w: os.Stdout}
+		}
+	} else if outFilename != "" {
+		var file *generatedFile
+		file, err = createGeneratedFile(outFilename)
+		if err != nil {
+			return err
+		}
+		// The file is only written once everything has been generated, so a
+		// failure leaves the previous one in place.
+		defer func() {
+			if err == nil {
+				err = file.Close()
+			}
+		}()
+		out = file
+	}
+
+	tempDir, err := ioutil.TempDir("", "mib2go")
+	if err != nil {
+		return errors.Wrap(err, "Creating temporary directory")
+	}
+	defer os.RemoveAll(tempDir)
+	defer removeOnInterrupt(tempDir)()
+
+	modules, err := loadModules(args, tempDir)
+	if loadFailures, ok := err.(moduleErrors); ok && len(modules) > 0 {
+		failures = append(failures, loadFailures...)
+	} else if err != nil {
+		return err
+	}
+
+	if withImports {
+		modules, err = addImportedModules(modules)
+		if err != nil {
+			return err
+		}
+	}
+
+	if canonical {
+		sort.Slice(modules, func(i, j int) bool { return modules[i].Name < modules[j].Name })
+	}
+
+	filtered = nil
+	if len(onlyNodes) > 0 || len(excludeNodes) > 0 {
+		only, err := parseNamePatterns(onlyNodes)
+		if err != nil {
+			return err
+		}
+		exclude, err := parseNamePatterns(excludeNodes)
+		if err != nil {
+			return err
+		}
+		filtered = dependencyClosure(selectNodes(modules, only, exclude))
+	}
+
+	if outputFormat == "json" {
+		return generateJSON(modules, out)
+	}
+
+	shared := &sharedDecls{
+		types:        make(map[string]*models.Type),
+		varNames:     make(map[nodeKey]string),
+		typeNames:    make(map[nodeKey]string),
+		generated:    make(map[string]bool),
+		external:     make(map[nodeKey]gosmi.SmiNode),
+		descriptions: make(map[string]string),
+		oidIndex:     make(map[string]string),
+	}
+	for _, module := range modules {
+		shared.generated[module.Name] = true
+	}
+
+	// Node vars only share a package without --subpackages.
+	if !subpackages {
+		err = shared.resolveVarNames(modules)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = shared.resolveTypeNames(modules)
+	if err != nil {
+		return err
+	}
+
+	if verifyOids {
+		err = verifyNodeOids(modules)
+		if err != nil {
+			return err
+		}
+	}
+
+	// With a single output, everything is collected first, as the imports of
+	// all modules have to be known before the header can be written.
+	outBuf := &bytes.Buffer{}
+	outImports := imports{}
+
+	var subpackageFiles []*subpackageFile
+
+	writer := newFileWriter(jobs)
+	defer writer.wait()
+
+	for _, module := range modules {
+		fileBuf, fileImports := outBuf, outImports
+		if out == nil {
+			fileBuf, fileImports = &bytes.Buffer{}, imports{}
+		} else if fileBuf.Len() > 0 {
+			// Modules sharing an output are separated by a blank line,
+			// just like any other top-level declarations.
+			fileBuf.WriteString("\n")
+		}
+		if out != nil && stdoutSplit {
+			fmt.Fprintf(fileBuf, "%s\n\n", moduleDelimiter(module.Name))
+		}
+
+		start := fileBuf.Len()
+		generateMibFile(module, fileBuf, shared, fileImports)
+
+		if verifyRoundTrips {
+			err = verifyRoundTrip(module, shared, fileBuf.Bytes()[start:])
+			if err != nil && !keepGoing {
+				return err
+			}
+			// The module is still generated, as other modules and the
+			// shared declarations may already refer to it.
 			if err != nil {
-				return errors.Wrapf(err, "Opening file %s", outFilename)
+				failures = append(failures, err)
 			}
-			defer out.Close()
-			log.Printf("Outputting to %s\n", outFilename)
 		}
 
-		typesMap := make(map[string]*models.Type)
+		if subpackages {
+			subpackageFiles = append(subpackageFiles, &subpackageFile{
+				name:    subpackageName(module.Name),
+				imports: fileImports,
+				body:    fileBuf.Bytes(),
+			})
+		} else if out == nil {
+			filename := path.Join(outDir, strings.ToLower(module.Name)+".go")
+			writer.write(filename, packageName, fileImports, fileBuf.Bytes())
+		}
+	}
+
+	for _, err := range writer.wait() {
+		err = errors.Wrap(err, "Writing module Go file")
+		if !keepGoing {
+			return err
+		}
+		failures = append(failures, err)
+	}
+
+	typesBuf, typesImports := outBuf, outImports
+	if out == nil {
+		typesBuf, typesImports = &bytes.Buffer{}, imports{}
+	} else if typesBuf.Len() > 0 {
+		typesBuf.WriteString("\n")
+	}
+
+	keys := make([]string, 0, len(shared.types))
+	for k := range shared.types {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		t := shared.types[key]
+		typesImports.add(modelsImport, typesImport)
+		generateTypeBlock(typesBuf, t, key)
+		if (emitEnumConsts || enumStrings || enumParse) && t.BaseType == types.BaseTypeEnum && t.Enum != nil {
+			generateEnumType(typesBuf, typesImports, formatNodeName(key), t.Enum)
+		}
+		if emitEnumConsts && t.BaseType == types.BaseTypeBits && t.Enum != nil {
+			generateBitsConsts(typesBuf, formatNodeName(key), t.Enum)
+		}
+		if emitEnumLabels && t.Enum != nil {
+			generateEnumLabels(typesBuf, formatTypeVarName(key), t.Enum)
+		}
+		if displayHints && hasDisplayHint(t) {
+			generateFormatValue(typesBuf, formatNodeName(key), t)
+		}
+		if rangeValidators {
+			generateRangeValidator(typesBuf, typesImports, formatNodeName(key)+"Validate", key, t)
+		}
+	}
+
+	if emitModuleInfo && shared.lastUpdated {
+		typesImports.add(modelsImport, "time")
+		typesBuf.WriteString(moduleInfoDecls)
+	} else if emitModuleInfo {
+		typesImports.add(modelsImport)
+		typesBuf.WriteString(undatedModuleInfoDecls)
+	}
+
+	if shared.groups {
+		typesImports.add(modelsImport, typesImport)
+		typesBuf.WriteString(groupDecls)
+	}
+
+	if shared.nodeInfo {
+		typesImports.add(modelsImport, typesImport)
+		typesBuf.WriteString(nodeInfoDecls)
+	}
+
+	if shared.trapDecoders {
+		typesImports.add(gosnmpImport, "strings")
+		typesBuf.WriteString(trapDecoderDecls)
+	}
+
+	if shared.compliances {
+		typesImports.add(modelsImport, typesImport)
+		typesBuf.WriteString(complianceDecls)
+	}
+
+	if displayHints {
+		typesImports.add("fmt", "strings")
+		typesBuf.WriteString(displayHintDecls)
+	}
+
+	if smiTypes {
+		typesBuf.WriteString(smiTypeDecls)
+	}
+
+	if shared.timeTicks {
+		typesImports.add("time")
+		typesBuf.WriteString(timeTicksDecls)
+	}
+
+	if len(shared.external) > 0 {
+		typesImports.add(modelsImport, typesImport)
+		generateExternalStubs(typesBuf, typesImports, shared)
+	}
+
+	if emitTablesMap {
+		typesImports.add(modelsImport)
+		generateTablesMap(typesBuf, shared)
+	}
+
+	if emitCellOid {
+		typesImports.add(modelsImport, typesImport, "fmt", "reflect")
+		typesBuf.WriteString(cellOidDecls)
+	}
+
+	if emitAssertions {
+		typesImports.add(modelsImport)
+		typesBuf.WriteString(baseNodeAssertions)
+		for _, kind := range modelsAssertions {
+			if shared.nodeKinds&kind.kind > 0 {
+				typesBuf.WriteString(kind.assertions)
+			}
+		}
+	}
+
+	if emitRegister {
+		typesBuf.WriteString(registryDecls)
+	}
+
+	if emitTableRanges {
+		typesImports.add(modelsImport)
+		typesBuf.WriteString(oidRangeDecls)
+	}
+
+	if emitIndexStructs {
+		typesImports.add(modelsImport, "fmt", "strconv", "strings")
+		typesBuf.WriteString(indexCodecDecls)
+	}
+
+	if emitResolve {
+		typesImports.add(modelsImport)
+		fmt.Fprintf(typesBuf, "var resolvableNodes = []models.BaseNode{\n")
+		for _, ref := range shared.resolvable {
+			fmt.Fprintf(typesBuf, "\t%s,\n", ref)
+		}
+		fmt.Fprintf(typesBuf, "}\n\n")
+		typesBuf.WriteString(resolveDecls)
+	}
+
+	if emitNotifyDecoder {
+		typesImports.add(modelsImport)
+		generateNotificationsMap(typesBuf, shared)
+		typesBuf.WriteString(notificationDecoder)
+	}
+
+	if standalone {
+		typesImports.add("strconv", "strings", "time")
+		typesBuf.WriteString(standaloneDecls)
+	}
+
+	if descriptionsFile {
+		descriptionsBuf := outBuf
+		if out == nil {
+			descriptionsBuf = &bytes.Buffer{}
+		} else {
+			descriptionsBuf.WriteString("\n")
+		}
+
+		generateDescriptions(descriptionsBuf, shared)
+
+		if out == nil {
+			filename := path.Join(outDir, "descriptions.go")
+			err = writeGeneratedFile(filename, nil, packageName, imports{}, descriptionsBuf.Bytes())
+			if err != nil {
+				return errors.Wrap(err, "Writing descriptions Go file")
+			}
+		}
+	}
+
+	if emitOidIndex {
+		indexBuf, indexImports := outBuf, outImports
+		if out == nil {
+			indexBuf, indexImports = &bytes.Buffer{}, imports{}
+		} else {
+			indexBuf.WriteString("\n")
+		}
+
+		indexImports.add(modelsImport)
+		generateOidIndex(indexBuf, shared)
+
+		if out == nil {
+			filename := path.Join(outDir, "index.go")
+			err = writeGeneratedFile(filename, nil, packageName, indexImports, indexBuf.Bytes())
+			if err != nil {
+				return errors.Wrap(err, "Writing index Go file")
+			}
+		}
+	}
+
+	if emitModulesMap {
+		modulesBuf := outBuf
+		if out == nil {
+			modulesBuf = &bytes.Buffer{}
+		} else {
+			modulesBuf.WriteString("\n")
+		}
+
+		generateModulesMap(modulesBuf, modules)
+
+		if out == nil {
+			filename := path.Join(outDir, "modules.go")
+			err = writeGeneratedFile(filename, nil, packageName, imports{}, modulesBuf.Bytes())
+			if err != nil {
+				return errors.Wrap(err, "Writing modules Go file")
+			}
+		}
+	}
+
+	warnUnusedRenames()
+
+	if out != nil {
+		err = writeGeneratedFile("", out, packageName, outImports, outBuf.Bytes())
+		if err != nil {
+			return errors.Wrap(err, "Writing Go file")
+		}
+		return nil
+	}
+
+	if subpackages {
+		return writeSubpackages(subpackageFiles, typesImports, typesBuf.Bytes())
+	}
+
+	err = writeGeneratedFile(path.Join(outDir, "types.go"), nil, packageName, typesImports, typesBuf.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "Writing types Go file")
+	}
+
+	return nil
+}
+
+// writeSubpackages writes the modules generated with --subpackages and the
+// shared declarations into their subpackages, after qualifying references
+// between them. Nothing is written if the modules import each other in a cycle.
+func writeSubpackages(files []*subpackageFile, typesImports imports, typesBody []byte) error {
+	typesNames, err := declaredNames(typesBody)
+	if err != nil {
+		return err
+	}
+
+	packages := make(map[string]string, len(files))
+	for _, file := range files {
+		packages[file.name] = path.Join(importPath, file.name)
+	}
+
+	deps := make(map[string][]string, len(files))
+	for _, file := range files {
+		deps[file.name], err = qualifySubpackageRefs(file, typesNames, packages)
+		if err != nil {
+			return errors.Wrapf(err, "Qualifying references of subpackage %s", file.name)
+		}
+	}
+	if cycle := findImportCycle(deps); cycle != nil {
+		return errors.Errorf("Import cycle between subpackages %s", strings.Join(cycle, " -> "))
+	}
+
+	for _, file := range files {
+		filename := path.Join(outDir, file.name, file.name+".go")
+		err = writeGeneratedFile(filename, nil, file.name, file.imports, file.body)
+		if err != nil {
+			return errors.Wrap(err, "Writing module Go file")
+		}
+	}
+
+	filename := path.Join(outDir, typesSubpackage, "types.go")
+	err = writeGeneratedFile(filename, nil, typesSubpackage, typesImports, typesBody)
+	if err != nil {
+		return errors.Wrap(err, "Writing types Go file")
+	}
+
+	return nil
+}
+
+// setSearchPath sets up the MIB search path from the -M and --override-path
+// flags, with searchPaths searched before any of them.
+func setSearchPath(searchPaths []string) {
+	for _, path := range paths {
+		gosmi.AppendPath(path)
+	}
+
+	// libsmi searches its default path before anything appended to it, so
+	// overrides have to be prepended, last one first to keep their order.
+	prepend := append(append([]string{}, searchPaths...), overrides...)
+	for i := len(prepend) - 1; i >= 0; i-- {
+		gosmi.PrependPath(prepend[i])
+	}
+}
+
+// loadModules loads the modules given by name or path in args. Files are
+// normalized into tempDir first if needed. With --keep-going, the modules that
+// failed to load are skipped and returned as moduleErrors along with the
+// others.
+func loadModules(args []string, tempDir string) ([]gosmi.SmiModule, error) {
+	modules := make([]gosmi.SmiModule, 0, len(args))
+	loaded := make(map[string]bool, len(args))
+	var failures moduleErrors
+	for _, arg := range args {
+		module, err := loadModule(arg, tempDir)
+		if err != nil {
+			if !keepGoing {
+				return nil, err
+			}
+			failures = append(failures, err)
+			continue
+		}
+
+		if !loaded[module.Name] {
+			loaded[module.Name] = true
+			modules = append(modules, module)
+		}
+	}
+
+	for _, module := range gosmi.GetLoadedModules() {
+		log.Printf("Loaded module %s from %s\n", module.Name, module.Path)
+	}
+
+	if len(failures) > 0 {
+		return modules, failures
+	}
+	return modules, nil
+}
+
+// mibFileExtensions are the extensions that make an arg without a path
+// separator a MIB file rather than the name of a module.
+var mibFileExtensions = map[string]bool{".mib": true, ".my": true, ".txt": true, ".smi": true}
+
+// isMibFileArg reports whether arg names a MIB file to load rather than a
+// module to search for, so a file in the working directory that happens to be
+// named like a module doesn't take the place of the module on the search path.
+func isMibFileArg(arg string) bool {
+	return strings.ContainsRune(arg, '/') || strings.ContainsRune(arg, filepath.Separator) ||
+		mibFileExtensions[strings.ToLower(filepath.Ext(arg))]
+}
+
+func loadModule(arg string, tempDir string) (gosmi.SmiModule, error) {
+	filename := arg
+	if fileInfo, err := os.Stat(arg); isMibFileArg(arg) && err == nil && !fileInfo.IsDir() {
+		filename, err = normalizeMibFile(arg, tempDir)
+		if err != nil {
+			return gosmi.SmiModule{}, errors.Wrapf(err, "Normalizing module %s", arg)
+		}
+	}
+
+	moduleName, err := gosmi.LoadModule(filename)
+	if err != nil {
+		return gosmi.SmiModule{}, errors.Wrapf(err, "Loading module %s", filename)
+	}
+
+	module, err := gosmi.GetModule(moduleName)
+	if err != nil {
+		return gosmi.SmiModule{}, errors.Wrapf(err, "Getting module %s", moduleName)
+	}
+	return module, nil
+}
+
+// moduleErrors are the errors of the modules that failed with --keep-going,
+// which are reported together once the others have been generated.
+type moduleErrors []error
+
+func (e moduleErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d modules failed:\n%s", len(e), strings.Join(messages, "\n"))
+}
+
+// moduleDelimiter returns the comment put in front of the code of moduleName
+// with --stdout-split.
+func moduleDelimiter(moduleName string) string {
+	return "// --- MODULE: " + moduleName + " ---"
+}
+
+// readModuleList reads the modules listed in filename for --from-file.
+func readModuleList(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Opening module list %s", filename)
+	}
+	defer file.Close()
+
+	var modules []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			modules = append(modules, line)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "Reading module list %s", filename)
+	}
+
+	return modules, nil
+}
+
+// uniqueArgs drops the args given before, keeping the order of the rest.
+func uniqueArgs(args []string) []string {
+	seen := make(map[string]bool, len(args))
+	unique := make([]string, 0, len(args))
+	for _, arg := range args {
+		if !seen[arg] {
+			seen[arg] = true
+			unique = append(unique, arg)
+		}
+	}
+	return unique
+}
+
+// addImportedModules adds the modules imported by modules, directly or not,
+// for --with-imports. Every module comes after the modules it imports, which
+// is why import cycles are an error.
+func addImportedModules(modules []gosmi.SmiModule) ([]gosmi.SmiModule, error) {
+	byName := make(map[string]gosmi.SmiModule)
+	deps := make(map[string][]string)
+	queue := append([]gosmi.SmiModule(nil), modules...)
+	for len(queue) > 0 {
+		module := queue[0]
+		queue = queue[1:]
+		if _, ok := byName[module.Name]; ok {
+			continue
+		}
+		byName[module.Name] = module
+		deps[module.Name] = nil
+
+		imported := make(map[string]bool)
+		for _, moduleImport := range module.GetImports() {
+			if smiLanguageModules[moduleImport.Module] || imported[moduleImport.Module] {
+				continue
+			}
+			imported[moduleImport.Module] = true
+
+			importedModule, err := gosmi.GetModule(moduleImport.Module)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Getting module %s imported by %s", moduleImport.Module, module.Name)
+			}
+			deps[module.Name] = append(deps[module.Name], moduleImport.Module)
+			queue = append(queue, importedModule)
+		}
+	}
+
+	if cycle := findImportCycle(deps); cycle != nil {
+		return nil, errors.Errorf("Import cycle between modules %s", strings.Join(cycle, " -> "))
+	}
+
+	ordered := make([]gosmi.SmiModule, 0, len(byName))
+	added := make(map[string]bool, len(byName))
+	var add func(name string)
+	add = func(name string) {
+		if added[name] {
+			return
+		}
+		added[name] = true
+		for _, dep := range deps[name] {
+			add(dep)
+		}
+		ordered = append(ordered, byName[name])
+	}
+	for _, module := range modules {
+		add(module.Name)
+	}
+	if len(ordered) > len(modules) {
+		given := make(map[string]bool, len(modules))
+		for _, module := range modules {
+			given[module.Name] = true
+		}
+		for _, module := range ordered {
+			if !given[module.Name] {
+				log.Printf("Adding module %s imported by the modules given\n", module.Name)
+			}
+		}
+	}
+
+	return ordered, nil
+}
+
+// writeGeneratedFile writes a Go file of package filePackage with the given
+// imports and body to out, or to filename if out is nil.
+func writeGeneratedFile(filename string, out io.Writer, filePackage string, imports imports, body []byte) error {
+	if standalone {
+		var err error
+		body, err = stripGosmiQualifiers(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	imports = referencedImports(imports, body)
+
+	if out != nil {
+		return writeGoFile(out, append(fileHeader(filePackage, imports), body...))
+	}
+
+	file, err := createGeneratedFile(filename)
+	if err != nil {
+		return err
+	}
+	err = writeGoFile(file, append(fileHeader(filePackage, imports), body...))
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// generatedFile collects a generated file in memory, which is written to
+// filename when it is closed.
+type generatedFile struct {
+	bytes.Buffer
+	filename string
+}
+
+// Close writes the file with writeOutputFile.
+func (f *generatedFile) Close() error {
+	return writeOutputFile(f.filename, f.Bytes())
+}
+
+// createGeneratedFile starts a generated file to be written to filename. The
+// //go:generate directives above the package clause of an existing file are
+// written back first, so a hand-written directive running mib2go survives
+// regenerating the file.
+func createGeneratedFile(filename string) (*generatedFile, error) {
+	var directives []byte
+	existing, err := ioutil.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "Reading file %s", filename)
+	}
+	for _, line := range bytes.Split(existing, []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("package ")) {
+			break
+		}
+		if bytes.HasPrefix(line, []byte("//go:generate ")) {
+			directives = append(append(directives, bytes.TrimRight(line, "\r")...), '\n')
+		}
+	}
+
+	file := &generatedFile{filename: filename}
+	if len(directives) > 0 {
+		file.Write(append(directives, '\n'))
+	}
+
+	return file, nil
+}
+
+// makeOutputDir creates dir and its parents unless they exist or --dry-run
+// is given.
+func makeOutputDir(dir string) error {
+	if dryRun {
+		return nil
+	}
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return errors.Wrapf(err, "Creating directory %s", dir)
+	}
+	return nil
+}
+
+// writeOutputFile creates or truncates filename and writes b to it, creating
+// its directory first if needed. A file that already has the contents b is
+// left untouched, keeping its modification time for build tools. With
+// --dry-run, it only logs what would be written instead.
+func writeOutputFile(filename string, b []byte) error {
+	existing, err := ioutil.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "Reading file %s", filename)
+	}
+	if err == nil && bytes.Equal(existing, b) {
+		log.Printf("Unchanged %s\n", filename)
+		return nil
+	}
+
+	if dryRun {
+		action := "create"
+		if err == nil {
+			action = "overwrite"
+		}
+		log.Printf("Would %s %s with %d bytes\n", action, filename, len(b))
+		return nil
+	}
+
+	err = makeOutputDir(filepath.Dir(filename))
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "Opening file %s", filename)
+	}
+	defer file.Close()
+	defer removeOnInterrupt(filename)()
+	log.Printf("Outputting to %s\n", filename)
+
+	_, err = file.Write(b)
+	if err != nil {
+		return errors.Wrapf(err, "Writing file %s", filename)
+	}
+
+	return file.Close()
+}
+
+func formatModuleName(moduleName string) (formattedName string) {
+	if goName, ok := renameOf(moduleName); ok {
+		return upperFirst(goName)
+	}
+	parts := strings.Split(moduleName, "-")
+	for _, part := range parts {
+		// Leading, trailing or doubled hyphens leave empty parts behind.
+		if part == "" {
+			continue
+		}
+		if preserveAcronyms && isAcronym(part) {
+			formattedName += strings.ToUpper(part)
+			continue
+		}
+		formattedName += strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+	}
+	return prefixDigit(formattedName)
+}
+
+// isAcronym reports whether part of a module name is one of the acronyms kept
+// upper-case with --preserve-acronyms, in any case.
+func isAcronym(part string) bool {
+	for _, acronym := range acronyms {
+		if strings.EqualFold(part, acronym) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateComment emits description as a block comment, or nothing at all if
+// it is empty.
+func generateComment(buf io.Writer, description string) {
+	if strings.TrimSpace(description) == "" {
+		return
+	}
+	fmt.Fprintf(buf, "/*\n%s\n*/\n", formatComment(description))
+}
+
+func formatComment(comment string) string {
+	comment = string(normalizeEncoding([]byte(comment)))
+	for _, replacer := range commentReplacers {
+		comment = replacer.Replace(comment)
+	}
+	if canonical {
+		comment = canonicalComment(comment)
+	}
+	if commentWidth > 0 {
+		comment = wrapComment(comment, commentWidth)
+	}
+	return comment
+}
+
+// wrapComment word-wraps the lines of comment that are longer than width
+// columns, continuing them with the indentation they started with. Words are
+// never broken, so a word longer than width is left on a line of its own.
+func wrapComment(comment string, width int) string {
+	lines := strings.Split(comment, "\n")
+	wrapped := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) <= width {
+			wrapped = append(wrapped, line)
+			continue
+		}
+
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		current := indent
+		for _, word := range strings.Fields(line) {
+			if current != indent && utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) > width {
+				wrapped = append(wrapped, current)
+				current = indent
+			}
+			if current != indent {
+				current += " "
+			}
+			current += word
+		}
+		wrapped = append(wrapped, current)
+	}
+	return strings.Join(wrapped, "\n")
+}
+
+// canonicalComment normalizes line endings, strips trailing whitespace from
+// every line and drops leading and trailing blank lines.
+func canonicalComment(comment string) string {
+	lines := strings.Split(strings.Replace(comment, "\r\n", "\n", -1), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+func formatNodeName(nodeName string) (formattedName string) {
+	if goName, ok := renameOf(nodeName); ok {
+		return sanitizeIdentifier(upperFirst(goName))
+	}
+	return sanitizeIdentifier(upperFirst(prefixDigit(nodeName)))
+}
+
+// formatNodeVarName returns the name of the var generated for a node, which
+// is never exported, as the node is reachable through its module var.
+func formatNodeVarName(nodeName string) (formattedName string) {
+	if goName, ok := renameOf(nodeName); ok {
+		return sanitizeIdentifier(lowerFirst(goName) + "Node")
+	}
+	return sanitizeIdentifier(lowerFirst(prefixDigit(nodeName)) + "Node")
+}
+
+// prefixDigit prefixes a name starting with a digit, which Go identifiers
+// can't, with an X. Vendor MIBs like those of 3GPP have such names.
+func prefixDigit(name string) string {
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		return "X" + name
+	}
+	return name
+}
+
+// upperFirst returns s with its first byte upper-cased, also for an empty s.
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// lowerFirst returns s with its first byte lower-cased, also for an empty s.
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// formatTypeVarName returns the name of the var holding a shared type, which
+// is exported unless only the module vars should be public, like node vars.
+func formatTypeVarName(typeName string) (formattedName string) {
+	if unexportedVars {
+		return lowerFirst(formatNodeName(typeName)) + "Type"
+	}
+	return formatNodeName(typeName) + "Type"
+}
+
+// sanitizeIdentifier suffixes name with an underscore if it is a Go keyword,
+// which can't be used as an identifier, or one of Go's predeclared
+// identifiers, which a var of that name would shadow. The lower-cased module
+// names of --subpackages are the ones that need it, as upper-casing and the
+// suffixes of node and type vars rule it out for the rest. Being a pure function of name keeps every reference to a
+// sanitized name in sync with its declaration.
+func sanitizeIdentifier(name string) string {
+	if token.IsKeyword(name) || predeclaredIdentifiers[name] {
+		return name + "_"
+	}
+	return name
+}
+
+// formatSyntax renders t the way it would be written in an SMI SYNTAX clause,
+// e.g. "INTEGER (0..65535)" or "OCTET STRING (SIZE(0..255))".
+func formatSyntax(t *models.Type) string {
+	syntax := t.Name
+	if keyword, ok := syntaxKeywords[t.BaseType]; ok && inlineTypeNames[t.Name] {
+		syntax = keyword
+	}
+
+	if t.Enum != nil && len(t.Enum.Values) > 0 {
+		values := make([]string, 0, len(t.Enum.Values))
+		for _, key := range t.Enum.Values.Keys() {
+			values = append(values, fmt.Sprintf("%s(%d)", t.Enum.Values[int64(key)], key))
+		}
+		syntax += " { " + strings.Join(values, ", ") + " }"
+	}
+
+	if len(t.Ranges) > 0 {
+		ranges := make([]string, len(t.Ranges))
+		for i, typeRange := range t.Ranges {
+			min := formatRangeBoundValue(typeRange.BaseType, typeRange.MinValue)
+			if typeRange.MinValue == typeRange.MaxValue {
+				ranges[i] = min
+			} else {
+				ranges[i] = min + ".." + formatRangeBoundValue(typeRange.BaseType, typeRange.MaxValue)
+			}
+		}
+		if t.BaseType == types.BaseTypeOctetString {
+			syntax += " (SIZE(" + strings.Join(ranges, " | ") + "))"
+		} else {
+			syntax += " (" + strings.Join(ranges, " | ") + ")"
+		}
+	}
+
+	return syntax
+}
+
+// resolveVarNames detects node vars of different modules that would end up with
+// the same name in the package. Those are reported, or qualified with the name
+// of their module if requested.
+func (s *sharedDecls) resolveVarNames(modules []gosmi.SmiModule) error {
+	owners := make(map[string][]nodeKey)
+	for _, module := range modules {
+		for _, node := range moduleNodes(module) {
+			if node.Kind&allowedNodeKinds > 0 {
+				varName := formatNodeVarName(node.Name)
+				owners[varName] = append(owners[varName], nodeKey{module.Name, node.Name})
+			}
+		}
+	}
+
+	var duplicates []string
+	for varName, keys := range owners {
+		if len(keys) < 2 {
+			continue
+		}
+		if qualifyDuplicates {
+			for _, key := range keys {
+				s.varNames[key] = formatNodeVarName(formatModuleName(key.module) + formatNodeName(key.name))
+			}
+			continue
+		}
+		definitions := make([]string, len(keys))
+		for i, key := range keys {
+			definitions[i] = key.module + "::" + key.name
+		}
+		duplicates = append(duplicates, fmt.Sprintf("%s (%s)", varName, strings.Join(definitions, ", ")))
+	}
+
+	if len(duplicates) > 0 {
+		sort.Strings(duplicates)
+		return errors.Errorf("Duplicate node var names, use --qualify-duplicates to prefix them with their module: %s", strings.Join(duplicates, "; "))
+	}
+
+	return nil
+}
+
+// resolveTypeNames detects shared types of different modules with the same
+// name but a different definition, which would otherwise be generated as one.
+// Those are reported, qualified with the name of their module or merged into
+// the first one found, depending on --on-type-conflict. Types are looked up in
+// the modules defining them, which libsmi loads along with the modules
+// importing them, unless --resolve-imports=false requires those modules to be
+// generated as well. Of the same type defined by several modules, the
+// definition of the module first by name is generated, whatever order the
+// modules are given in.
+func (s *sharedDecls) resolveTypeNames(modules []gosmi.SmiModule) error {
+	definitions := make(map[nodeKey]*models.Type)
+	owners := make(map[string][]nodeKey)
+	var unresolved []string
+	for _, module := range modules {
+		for _, node := range moduleNodes(module) {
+			if node.Kind&(types.NodeColumn|types.NodeScalar) == 0 {
+				continue
+			}
+			definition, overridden := typeDefinition(node)
+			key := typeKey(node)
+			if _, ok := definitions[key]; ok || inlineTypeNames[node.Type.Name] || overridden {
+				continue
+			}
+			definitions[key] = definition
+			owners[key.name] = append(owners[key.name], key)
+			if !resolveImports && !s.generated[key.module] {
+				unresolved = append(unresolved, fmt.Sprintf("%s (%s)", key.name, key.module))
+			}
+		}
+	}
+
+	if len(unresolved) > 0 {
+		sort.Strings(unresolved)
+		return errors.Errorf("Types defined by modules that aren't generated, add the modules or use --resolve-imports: %s", strings.Join(unresolved, "; "))
+	}
+
+	var conflicts []string
+	for typeName, keys := range owners {
+		conflicting := false
+		for _, key := range keys[1:] {
+			if !sameTypeDefinition(definitions[keys[0]], definitions[key]) {
+				conflicting = true
+			}
+		}
+		if !conflicting {
+			owner := keys[0]
+			for _, key := range keys[1:] {
+				if key.module < owner.module {
+					owner = key
+				}
+			}
+			s.types[typeName] = definitions[owner]
+			continue
+		}
+
+		moduleNames := make([]string, len(keys))
+		for i, key := range keys {
+			moduleNames[i] = key.module
+		}
+		switch typeConflicts {
+		case "rename":
+			for _, key := range keys {
+				s.typeNames[key] = formatModuleName(key.module) + upperFirst(typeName)
+				s.types[s.typeNames[key]] = definitions[key]
+			}
+		case "first":
+			s.types[typeName] = definitions[keys[0]]
+			log.Printf("Type %s is defined differently by %s, using the definition of %s\n", typeName, strings.Join(moduleNames, ", "), keys[0].module)
+		default:
+			conflicts = append(conflicts, fmt.Sprintf("%s (%s)", typeName, strings.Join(moduleNames, ", ")))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return errors.Errorf("Types with the same name but a different definition, use --on-type-conflict to prefix them with their module or use the first one: %s", strings.Join(conflicts, "; "))
+	}
+
+	return nil
+}
+
+// typeName returns the name the named type of a node is shared by, taking into
+// account types renamed by resolveTypeNames.
+func (s *sharedDecls) typeName(node gosmi.SmiNode) string {
+	if typeName, ok := s.typeNames[typeKey(node)]; ok {
+		return typeName
+	}
+	return node.Type.Name
+}
+
+// typeKey identifies the named type of node by the module defining it.
+func typeKey(node gosmi.SmiNode) nodeKey {
+	if node.SmiType == nil {
+		return nodeKey{node.GetModule().Name, node.Type.Name}
+	}
+	return nodeKey{node.SmiType.GetModule().Name, node.Type.Name}
+}
+
+// sameTypeDefinition reports whether a and b define the same type, as far as
+// the generated code is concerned.
+func sameTypeDefinition(a *models.Type, b *models.Type) bool {
+	if a.BaseType != b.BaseType || a.Format != b.Format || a.Units != b.Units || !reflect.DeepEqual(a.Ranges, b.Ranges) {
+		return false
+	}
+	if a.Enum == nil || b.Enum == nil {
+		return a.Enum == b.Enum
+	}
+	return a.Enum.BaseType == b.Enum.BaseType && reflect.DeepEqual(a.Enum.Values, b.Enum.Values)
+}
+
+// nodeVarName returns the name of the var generated for a node, taking into
+// account vars qualified by resolveVarNames.
+func (s *sharedDecls) nodeVarName(moduleName string, nodeName string) string {
+	if varName, ok := s.varNames[nodeKey{moduleName, nodeName}]; ok {
+		return varName
+	}
+	return formatNodeVarName(nodeName)
+}
+
+// nodeRef returns an expression for the value of the var generated for a node,
+// which is a call of its accessor with --lazy. With --subpackages, nodes of
+// other modules are referenced through the module var of their subpackage, as
+// node vars aren't exported.
+func (s *sharedDecls) nodeRef(moduleName string, nodeName string) string {
+	ref := s.nodeVarName(moduleName, nodeName)
+	if subpackages && moduleName != s.module {
+		ref = subpackageName(moduleName) + "." + formatModuleName(moduleName) + "." + formatNodeName(nodeName)
+	}
+	if lazyNodes {
+		return ref + "()"
+	}
+	return ref
+}
+
+// refVarName returns a reference to the var of a node referenced from another
+// node. With --own-only, nodes of modules that aren't generated are recorded to
+// get a stub generated for them instead.
+func (s *sharedDecls) refVarName(node gosmi.SmiNode) string {
+	moduleName := node.GetModule().Name
+	if ownOnly && !s.generated[moduleName] {
+		s.external[nodeKey{moduleName, node.Name}] = node
+	}
+	return s.nodeRef(moduleName, node.Name)
+}
+
+// scalarRef returns a reference to the var of a scalar or column node as a
+// models.ScalarNode.
+func (s *sharedDecls) scalarRef(node gosmi.SmiNode) string {
+	varName := s.refVarName(node)
+	if node.Kind == types.NodeScalar {
+		return varName
+	}
+	return varName + ".ScalarNode"
+}
+
+// moduleNodes returns the nodes of module sorted by OID, which are only those
+// defined by the module itself with --own-only, leaving out skipped ones and
+// MODULE-COMPLIANCE statements without --conformance.
+func moduleNodes(module gosmi.SmiModule) []gosmi.SmiNode {
+	nodes := module.GetNodes()
+	kept := nodes[:0]
+	for _, node := range nodes {
+		if node.Kind == types.NodeCompliance && !conformance {
+			continue
+		}
+		if (!ownOnly || node.GetModule().Name == module.Name) && !skipped(node) {
+			kept = append(kept, node)
+		}
+	}
+	nodes = kept
+
+	sortNodes(nodes)
+	return nodes
+}
+
+// sortNodes sorts nodes by their OIDs, so the generated code doesn't depend on
+// the order gosmi returns them in.
+func sortNodes(nodes []gosmi.SmiNode) {
+	sort.SliceStable(nodes, func(i, j int) bool { return oidLess(nodes[i].Oid, nodes[j].Oid) })
+}
+
+// skipped reports whether node is left out for its STATUS by --skip-obsolete
+// or --skip-deprecated, or by --only and --exclude. References to skipped
+// nodes are dropped as well.
+func skipped(node gosmi.SmiNode) bool {
+	if filtered != nil && !filtered[nodeKey{node.GetModule().Name, node.Name}] {
+		return true
+	}
+	return statusSkipped(node)
+}
+
+// statusSkipped reports whether node is left out for its STATUS.
+func statusSkipped(node gosmi.SmiNode) bool {
+	return (skipObsolete && node.Status == types.StatusObsolete) ||
+		(skipDeprecated && node.Status == types.StatusDeprecated)
+}
+
+// oidLess reports whether a sorts before b, comparing sub-identifiers
+// numerically, with a prefix sorting before any longer OID.
+func oidLess(a models.Oid, b models.Oid) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// openNodeVar starts the declaration of the var of a node, which with --lazy is
+// an accessor initializing it on first use instead. With --oid-comments, the
+// opening brace is followed by a comment with oidFormatted, which keeps it
+// apart from the description above the declaration.
+func openNodeVar(buf io.Writer, imports imports, varName string, kind types.NodeKind, oidFormatted string) {
+	comment := ""
+	if oidComments {
+		comment = " // " + oidFormatted
+	}
+
+	if !lazyNodes {
+		fmt.Fprintf(buf, "var %s = %s{%s\n", varName, nodeTypeName(kind), comment)
+		return
+	}
+
+	imports.add("sync")
+	fmt.Fprintf(buf, "var (\n")
+	fmt.Fprintf(buf, "\t%sOnce sync.Once\n", varName)
+	fmt.Fprintf(buf, "\t%sValue %s\n", varName, nodeTypeName(kind))
+	fmt.Fprintf(buf, ")\n\n")
+	fmt.Fprintf(buf, "func %s() %s {%s\n", varName, nodeTypeName(kind), comment)
+	fmt.Fprintf(buf, "\t%sOnce.Do(func() {\n", varName)
+	fmt.Fprintf(buf, "\t\t%sValue = %s{\n", varName, nodeTypeName(kind))
+}
+
+// nodeTypeName returns the type of the var of a node of the given kind. gosmi
+// has no model for groups and compliances, so GroupNode and ComplianceNode are
+// declared in the types file.
+func nodeTypeName(kind types.NodeKind) string {
+	if !hasNodeModel(kind) {
+		return kind.String() + "Node"
+	}
+	return "models." + kind.String() + "Node"
+}
+
+// hasNodeModel returns whether gosmi has a model for nodes of the given kind.
+func hasNodeModel(kind types.NodeKind) bool {
+	return kind != types.NodeGroup && kind != types.NodeCompliance
+}
+
+// generateNodeInfo declares the NodeInfo of the node var varName with the
+// fields written to info, if there are any.
+func generateNodeInfo(buf io.Writer, shared *sharedDecls, varName string, info *bytes.Buffer) {
+	if info.Len() == 0 {
+		return
+	}
+
+	shared.nodeInfo = true
+	fmt.Fprintf(buf, "var %sInfo = NodeInfo{\n", varName)
+	buf.Write(info.Bytes())
+	fmt.Fprintf(buf, "}\n")
+}
+
+// closeNodeVar ends a declaration started by openNodeVar. The closing brace
+// of the literal of a lazy node is indented, as only top-level declarations
+// may end in a closing brace on a line of its own.
+func closeNodeVar(buf io.Writer, varName string) {
+	if !lazyNodes {
+		fmt.Fprintf(buf, "}\n")
+		return
+	}
+
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t})\n")
+	fmt.Fprintf(buf, "\treturn %sValue\n", varName)
+	fmt.Fprintf(buf, "}\n")
+}
+
+func generateMibFile(module gosmi.SmiModule, buf io.Writer, shared *sharedDecls, imports imports) {
+	imports.add(typesImport)
+	formattedModuleName := formatModuleName(module.Name)
+	nodes := moduleNodes(module)
+	shared.module = module.Name
+
+	if descriptionsFile {
+		if identity, ok := module.GetIdentityNode(); ok {
+			shared.descriptions[identity.RenderNumeric()] = module.Description
+		}
+	} else if !noDescriptions {
+		generateComment(buf, module.Description)
+	}
+
+	fmt.Fprintf(buf, "type %sModule struct {\n", formattedModuleName)
+	for _, node := range nodes {
+		if node.Kind&allowedNodeKinds > 0 {
+			imports.add(modelsImport)
+			typeName := nodeTypeName(node.Kind)
+			if _, ok := tableTypeColumns(node); ok {
+				typeName = tableTypeName(shared, module.Name, node.Name)
+			}
+			if lazyNodes {
+				fmt.Fprintf(buf, "\t%s\tfunc() %s\n", formatNodeName(node.Name), typeName)
+			} else {
+				fmt.Fprintf(buf, "\t%s\t%s\n", formatNodeName(node.Name), typeName)
+			}
+		}
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "var %s = %sModule {\n", formattedModuleName, formattedModuleName)
+	for _, node := range nodes {
+		if node.Kind&allowedNodeKinds > 0 {
+			value := shared.nodeVarName(module.Name, node.Name)
+			if columns, ok := tableTypeColumns(node); ok {
+				value = tableTypeLiteral(shared, module.Name, node, columns)
+			}
+			fmt.Fprintf(buf, "\t%s:\t%s,\n", formatNodeName(node.Name), value)
+		}
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	for _, node := range nodes {
+		if columns, ok := tableTypeColumns(node); ok {
+			generateTableType(buf, shared, module.Name, node, columns)
+		}
+	}
+
+	if emitNodeLookup {
+		generateNodeLookup(buf, imports, module, nodes, shared)
+	}
+	if emitKindAccessors {
+		generateKindAccessors(buf, imports, module, nodes, shared)
+	}
+
+	if emitLanguage {
+		fmt.Fprintf(buf, "// %sLanguage is the SMI version %s is written in.\n", formattedModuleName, module.Name)
+		fmt.Fprintf(buf, "const %sLanguage = types.Language%s\n\n", formattedModuleName, module.Language)
+	}
+
+	if rootOid, identity := moduleRootOid(module, nodes); emitModuleOid && len(rootOid) > 0 {
+		imports.add(modelsImport)
+		if identity {
+			fmt.Fprintf(buf, "// %sModuleOid is the OID of the MODULE-IDENTITY of %s.\n", formattedModuleName, module.Name)
+		} else {
+			fmt.Fprintf(buf, "// %sModuleOid is the longest common prefix of the OIDs of the nodes of %s,\n", formattedModuleName, module.Name)
+			fmt.Fprintf(buf, "// which has no MODULE-IDENTITY.\n")
+		}
+		fmt.Fprintf(buf, "var %sModuleOid = models.Oid{%s}\n\n", formattedModuleName, formatSubIDs(rootOid))
+		fmt.Fprintf(buf, "// %sModuleOidFormatted is %sModuleOid in dotted notation.\n", formattedModuleName, formattedModuleName)
+		fmt.Fprintf(buf, "const %sModuleOidFormatted = %q\n\n", formattedModuleName, rootOid.String())
+	}
+
+	if emitModuleInfo {
+		generateModuleInfo(buf, imports, shared, module)
+	}
+
+	if enterprise, ok := enterpriseNumber(module, nodes); emitEnterprise && ok {
+		fmt.Fprintf(buf, "// %sEnterprise is the private enterprise number %s is defined under.\n", formattedModuleName, module.Name)
+		fmt.Fprintf(buf, "const %sEnterprise uint32 = %d\n\n", formattedModuleName, enterprise)
+	}
+
+	if nativeTypes {
+		var scalars []gosmi.SmiNode
+		for _, node := range nodes {
+			if node.Kind == types.NodeScalar {
+				scalars = append(scalars, node)
+			}
+		}
+		fmt.Fprintf(buf, "// %sValues holds the values of the scalars of %s.\n", formattedModuleName, module.Name)
+		generateNativeStruct(buf, formattedModuleName+"Values", scalars)
+	}
+
+	if emitRegister {
+		fmt.Fprintf(buf, "func init() {\n")
+		fmt.Fprintf(buf, "\tRegister(%q, %s)\n", module.Name, formattedModuleName)
+		fmt.Fprintf(buf, "}\n\n")
+	}
+
+	for _, node := range nodes {
+		if node.Kind&allowedNodeKinds == 0 {
+			continue
+		}
+		shared.nodeKinds |= node.Kind
+
+		if smiTypes && node.Kind&(types.NodeColumn|types.NodeScalar) > 0 {
+			if smiType, _ := smiApplicationType(node); smiType == "TimeTicks" {
+				shared.timeTicks = true
+			}
+		}
+
+		if descriptionsFile {
+			shared.descriptions[node.RenderNumeric()] = node.Description
+		} else if !noDescriptions {
+			generateComment(buf, node.Description)
+		}
+		oid, oidFormatted, oidLen := instanceOid(node)
+		openNodeVar(buf, imports, shared.nodeVarName(module.Name, node.Name), node.Kind, oidFormatted)
+
+		// What the gosmi model of the node has no field for goes into its
+		// NodeInfo instead.
+		var info bytes.Buffer
+		fields := io.Writer(&info)
+		if !hasNodeModel(node.Kind) {
+			fields = buf
+		}
+
+		if node.Kind&types.NodeColumn > 0 {
+			fmt.Fprintf(buf, "\tScalarNode: models.ScalarNode{\n")
+		}
+
+		fmt.Fprintf(buf, "\tBaseNode: models.BaseNode{\n")
+		fmt.Fprintf(buf, "\t\tName: %q,\n", node.Name)
+		if emitOidIndex {
+			shared.oidIndex[oidFormatted] = shared.nodeRef(module.Name, node.Name) + ".BaseNode"
+		}
+		if emitResolve && node.Kind&(types.NodeColumn|types.NodeScalar) > 0 {
+			shared.resolvable = append(shared.resolvable, shared.nodeRef(module.Name, node.Name)+".BaseNode")
+		}
+		fmt.Fprintf(buf, "\t\tOid: %#v,\n", oid)
+		fmt.Fprintf(buf, "\t\tOidFormatted: %q,\n", oidFormatted)
+		fmt.Fprintf(buf, "\t\tOidLen: %d,\n", oidLen)
+		fmt.Fprintf(buf, "\t},\n")
+		if emitNodeIDs {
+			fmt.Fprintf(fields, "\tID: %q,\n", nodeID(module.Name, node.Oid))
+		}
+		// Groups and compliances have a Status of their own.
+		if emitNodeInfo || !hasNodeModel(node.Kind) {
+			fmt.Fprintf(fields, "\tStatus: types.Status%s,\n", node.Status)
+		}
+
+		if node.Kind&(types.NodeColumn|types.NodeScalar) > 0 {
+			if _, overridden := typeDefinition(node); inlineTypeNames[node.Type.Name] || overridden {
+				generateTypeBlock(buf, node.Type, "")
+			} else {
+				fmt.Fprintf(buf, "\tType: %s,\n", formatTypeVarName(shared.typeName(node)))
+			}
+			if emitSyntax {
+				fmt.Fprintf(fields, "\tSyntax: %q,\n", formatSyntax(node.Type))
+			}
+			// Index columns are commonly not-accessible, and objects only
+			// sent in notifications accessible-for-notify.
+			if emitNodeInfo {
+				fmt.Fprintf(fields, "\tAccess: types.Access%s,\n", node.Access)
+			}
+			if defaultValue, ok := nodeDefault(node); ok {
+				fmt.Fprintf(fields, "\tDefault: %s,\n", defaultValue)
+			}
+		} else if node.Kind == types.NodeTable {
+			if row := node.GetRow(); !skipped(row) {
+				fmt.Fprintf(buf, "\tRow: %s,\n", shared.nodeRef(module.Name, row.Name))
+				shared.tables = append(shared.tables, node)
+			}
+		} else if node.Kind == types.NodeRow {
+			fmt.Fprintf(buf, "\tColumns: []models.ColumnNode{\n")
+			columns, columnOrder := node.GetColumns()
+			for _, column := range columnOrder {
+				if !skipped(columns[column]) {
+					fmt.Fprintf(buf, "\t\t%s,\n", shared.nodeRef(module.Name, column))
+				}
+			}
+			fmt.Fprintf(buf, "\t},\n")
+			fmt.Fprintf(buf, "\tIndex: []models.ColumnNode{\n")
+			indices, base, inherited := rowIndex(node)
+			for _, index := range indices {
+				if !skipped(index) {
+					fmt.Fprintf(buf, "\t\t%s,\n", shared.refVarName(index))
+				}
+			}
+			fmt.Fprintf(buf, "\t},\n")
+			// The last index column isn't prefixed with its length if it
+			// is IMPLIED, which decoding the index depends on.
+			if rowImplied(node) {
+				fmt.Fprintf(buf, "\tImplied: true,\n")
+			}
+			// Rows that AUGMENT another row inherit its index.
+			if inherited && !skipped(base) {
+				fmt.Fprintf(fields, "\tAugments: %s.BaseNode,\n", shared.refVarName(base))
+			}
+		} else if node.Kind == types.NodeGroup {
+			// libsmi lists the members of a group as its elements, which
+			// gosmi returns for any node, not just notifications.
+			shared.groups = true
+			fmt.Fprintf(buf, "\tObjects: []models.BaseNode{\n")
+			for _, member := range node.GetNotificationObjects() {
+				if !skipped(member) {
+					fmt.Fprintf(buf, "\t\t%s.BaseNode,\n", shared.refVarName(member))
+				}
+			}
+			fmt.Fprintf(buf, "\t},\n")
+		} else if node.Kind == types.NodeCompliance {
+			generateCompliance(buf, shared, node)
+		} else if node.Kind == types.NodeNotification {
+			shared.notifications = append(shared.notifications, node)
+			objects := node.GetNotificationObjects()
+			var notifyOnly []gosmi.SmiNode
+			fmt.Fprintf(buf, "\tObjects: []models.ScalarNode{\n")
+			for _, object := range objects {
+				if skipped(object) {
+					continue
+				}
+				fmt.Fprintf(buf, "\t\t%s,\n", shared.scalarRef(object))
+				if object.Access == types.AccessNotify {
+					notifyOnly = append(notifyOnly, object)
+				}
+			}
+			fmt.Fprintf(buf, "\t},\n")
+
+			// Objects that are accessible-for-notify can't be polled, they
+			// only ever appear in notifications.
+			if len(notifyOnly) > 0 {
+				fmt.Fprintf(fields, "\tNotifyOnly: []models.ScalarNode{\n")
+				for _, object := range notifyOnly {
+					fmt.Fprintf(fields, "\t\t%s,\n", shared.scalarRef(object))
+				}
+				fmt.Fprintf(fields, "\t},\n")
+			}
+
+			// libsmi registers an SMIv1 TRAP-TYPE at enterprise.0.specific,
+			// which already is its SMIv2 notification OID (RFC 3584), so the
+			// v1 identity is recovered from it.
+			if oidLen := len(node.Oid); node.Decl == types.DeclTrapType && oidLen > 2 && node.Oid[oidLen-2] == 0 {
+				fmt.Fprintf(fields, "\tEnterprise: %#v,\n", node.Oid[:oidLen-2])
+				fmt.Fprintf(fields, "\tSpecificTrap: %d,\n", node.Oid[oidLen-1])
+			}
+		}
+
+		if node.Kind&types.NodeColumn > 0 {
+			fmt.Fprintf(buf, "},\n")
+		}
+
+		closeNodeVar(buf, shared.nodeVarName(module.Name, node.Name))
+		generateNodeInfo(buf, shared, shared.nodeVarName(module.Name, node.Name), &info)
+
+		if trapDecoders && node.Kind == types.NodeNotification {
+			generateTrapDecoder(buf, imports, shared, node)
+		}
+
+		if oidType != "models" {
+			fmt.Fprintf(buf, "var %sOid = []%s{%s}\n", shared.nodeVarName(module.Name, node.Name), oidSliceElementType(), formatSubIDs(oid))
+		}
+		if oidType == "gosnmp" {
+			fmt.Fprintf(buf, "const %sGoSnmpOid = %q\n", shared.nodeVarName(module.Name, node.Name), "."+oid.String())
+		}
+
+		// Arrays are sized to each OID, which makes them a distinct type per
+		// length and keeps them from being used interchangeably, but they
+		// can be copied onto the stack without a heap allocation.
+		if emitOidArrays {
+			elementType := oidSliceElementType()
+			if elementType == "models" {
+				elementType = "uint32"
+			}
+			fmt.Fprintf(buf, "var %sOidArray = [%d]%s{%s}\n", shared.nodeVarName(module.Name, node.Name), len(oid), elementType, formatSubIDs(oid))
+		}
+
+		if emitEnumLabels && node.Kind&(types.NodeColumn|types.NodeScalar) > 0 && node.Type.Enum != nil && inlineTypeNames[node.Type.Name] {
+			generateEnumLabels(buf, shared.nodeVarName(module.Name, node.Name), node.Type.Enum)
+		}
+
+		if (emitEnumConsts || enumStrings || enumParse) && node.Kind&(types.NodeColumn|types.NodeScalar) > 0 && node.Type.BaseType == types.BaseTypeEnum && node.Type.Enum != nil && inlineTypeNames[node.Type.Name] {
+			typeName := upperFirst(strings.TrimSuffix(shared.nodeVarName(module.Name, node.Name), "Node"))
+			generateEnumType(buf, imports, typeName, node.Type.Enum)
+		}
+
+		if emitEnumConsts && node.Kind&(types.NodeColumn|types.NodeScalar) > 0 && node.Type.BaseType == types.BaseTypeBits && node.Type.Enum != nil && inlineTypeNames[node.Type.Name] {
+			typeName := upperFirst(strings.TrimSuffix(shared.nodeVarName(module.Name, node.Name), "Node"))
+			generateBitsConsts(buf, typeName, node.Type.Enum)
+		}
+
+		if rangeValidators && node.Kind&(types.NodeColumn|types.NodeScalar) > 0 {
+			if _, overridden := typeDefinition(node); inlineTypeNames[node.Type.Name] || overridden {
+				typeName := upperFirst(strings.TrimSuffix(shared.nodeVarName(module.Name, node.Name), "Node"))
+				generateRangeValidator(buf, imports, typeName+"Validate", node.Name, node.Type)
+			}
+		}
+
+		if unitWrappers && node.Kind&(types.NodeColumn|types.NodeScalar) > 0 && node.Type.Units != "" {
+			typeName := upperFirst(strings.TrimSuffix(shared.nodeVarName(module.Name, node.Name), "Node")) + "UnitValue"
+			generateUnitWrapper(buf, imports, typeName, node)
+		}
+
+		if smiTypes && node.Kind&(types.NodeColumn|types.NodeScalar) > 0 {
+			typeName := upperFirst(strings.TrimSuffix(shared.nodeVarName(module.Name, node.Name), "Node")) + "Value"
+			generateSmiTypeAlias(buf, typeName, node)
+		}
+
+		if displayHints && node.Kind&(types.NodeColumn|types.NodeScalar) > 0 && hasDisplayHint(node.Type) {
+			if _, overridden := typeDefinition(node); inlineTypeNames[node.Type.Name] || overridden {
+				typeName := upperFirst(strings.TrimSuffix(shared.nodeVarName(module.Name, node.Name), "Node"))
+				generateFormatValue(buf, typeName, node.Type)
+			}
+		}
+
+		if nativeTypes && node.Kind == types.NodeRow {
+			columns, columnOrder := node.GetColumns()
+			var columnNodes []gosmi.SmiNode
+			for _, column := range columnOrder {
+				if !skipped(columns[column]) {
+					columnNodes = append(columnNodes, columns[column])
+				}
+			}
+			fmt.Fprintf(buf, "// %sValues holds the values of the columns of a row of %s.\n", formatNodeName(node.Name), node.Name)
+			generateNativeStruct(buf, formatNodeName(node.Name)+"Values", columnNodes)
+		}
+
+		if emitIndexStructs && node.Kind == types.NodeTable {
+			generateIndexStruct(buf, imports, node)
+		}
+
+		if emitTableRanges && node.Kind == types.NodeTable {
+			row := node.GetRow()
+			fmt.Fprintf(buf, "var %sRange = OidRange{\n", shared.nodeVarName(module.Name, node.Name))
+			fmt.Fprintf(buf, "\tStart: %#v,\n", row.Oid)
+			fmt.Fprintf(buf, "\tEnd: %#v,\n", nextSiblingOid(row.Oid))
+			fmt.Fprintf(buf, "}\n")
+		}
+	}
+}
+
+// verifyNodeOids checks that the OidFormatted of every node to be generated
+// parses back into its Oid, so a mismatch is caught before anything is written.
+func verifyNodeOids(modules []gosmi.SmiModule) error {
+	for _, module := range modules {
+		for _, node := range moduleNodes(module) {
+			if node.Kind&allowedNodeKinds == 0 {
+				continue
+			}
+
+			oid, oidFormatted, oidLen := instanceOid(node)
+			parts := strings.Split(oidFormatted, ".")
+			if len(parts) != len(oid) || oidLen != len(oid) {
+				return errors.Errorf("OID mismatch for %s::%s: %s has %d sub-identifiers, expected %d with length %d", module.Name, node.Name, oidFormatted, len(parts), len(oid), oidLen)
+			}
+			for i, part := range parts {
+				subID, err := strconv.ParseUint(part, 10, 32)
+				if err != nil {
+					return errors.Wrapf(err, "Parsing OID %s of %s::%s", oidFormatted, module.Name, node.Name)
+				}
+				if uint32(subID) != oid[i] {
+					return errors.Errorf("OID mismatch for %s::%s: %s does not match %v", module.Name, node.Name, oidFormatted, oid)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// enterprisesOid is the OID of iso.org.dod.internet.private.enterprises.
+var enterprisesOid = models.Oid{1, 3, 6, 1, 4, 1}
+
+// enterpriseNumber returns the private enterprise number the root of module
+// is registered under, if any. The root is the MODULE-IDENTITY, or the first
+// node for SMIv1 modules that don't have one.
+func enterpriseNumber(module gosmi.SmiModule, nodes []gosmi.SmiNode) (uint32, bool) {
+	root, ok := module.GetIdentityNode()
+	if !ok {
+		if len(nodes) == 0 {
+			return 0, false
+		}
+		root = nodes[0]
+	}
+
+	if len(root.Oid) <= len(enterprisesOid) {
+		return 0, false
+	}
+	for i, subID := range enterprisesOid {
+		if root.Oid[i] != subID {
+			return 0, false
+		}
+	}
+	return root.Oid[len(enterprisesOid)], true
+}
+
+// generateNodeLookup emits the Node method of the module struct of module,
+// looking up its nodes by their name in the MIB. The method is named
+// LookupNode instead if the module has a node whose field is named Node, see
+// accessorName.
+func generateNodeLookup(buf io.Writer, imports imports, module gosmi.SmiModule, nodes []gosmi.SmiNode, shared *sharedDecls) {
+	imports.add(modelsImport)
+	formattedModuleName := formatModuleName(module.Name)
+	mapName := lowerFirst(formattedModuleName) + "Nodes"
+	methodName := accessorName("Node", "Lookup", nodes)
+
+	// With --lazy, the nodes are only initialized once they are looked up.
+	valueType := "models.BaseNode"
+	if lazyNodes {
+		valueType = "func() models.BaseNode"
+	}
+	fmt.Fprintf(buf, "var %s = map[string]%s{\n", mapName, valueType)
+	for _, node := range nodes {
+		if node.Kind&allowedNodeKinds == 0 {
+			continue
+		}
+		ref := shared.nodeRef(module.Name, node.Name) + ".BaseNode"
+		if lazyNodes {
+			ref = fmt.Sprintf("func() models.BaseNode { return %s }", ref)
+		}
+		fmt.Fprintf(buf, "\t%q: %s,\n", node.Name, ref)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// %s returns the node of %s with the given name in the MIB.\n", methodName, module.Name)
+	fmt.Fprintf(buf, "func (%sModule) %s(name string) (models.BaseNode, bool) {\n", formattedModuleName, methodName)
+	fmt.Fprintf(buf, "\tnode, ok := %s[name]\n", mapName)
+	if lazyNodes {
+		fmt.Fprintf(buf, "\tif !ok {\n")
+		fmt.Fprintf(buf, "\t\treturn models.BaseNode{}, false\n")
+		fmt.Fprintf(buf, "\t}\n")
+		fmt.Fprintf(buf, "\treturn node(), true\n")
+	} else {
+		fmt.Fprintf(buf, "\treturn node, ok\n")
+	}
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// tableTypeColumns reports whether table gets a type of its own for its field
+// in the module, which it does with --flatten-tables and --cell-oid, and
+// returns the columns accessible from that type, which are only those of
+// --flatten-tables. Tables whose row is skipped keep the plain node type.
+func tableTypeColumns(table gosmi.SmiNode) ([]gosmi.SmiNode, bool) {
+	if !(flattenTables || emitCellOid) || table.Kind != types.NodeTable {
+		return nil, false
+	}
+	row := table.GetRow()
+	if skipped(row) {
+		return nil, false
+	}
+	if !flattenTables {
+		return nil, true
+	}
+	columns, columnOrder := row.GetColumns()
+	kept := make([]gosmi.SmiNode, 0, len(columnOrder))
+	for _, column := range columnOrder {
+		if !skipped(columns[column]) {
+			kept = append(kept, columns[column])
+		}
+	}
+	return kept, true
+}
+
+// tableTypeName returns the name of the type of a table of its own, see
+// tableTypeColumns, which has a Columns suffix if it is flattened.
+func tableTypeName(shared *sharedDecls, moduleName string, tableName string) string {
+	typeName := upperFirst(strings.TrimSuffix(shared.nodeVarName(moduleName, tableName), "Node"))
+	if flattenTables {
+		typeName += "Columns"
+	}
+	return typeName
+}
+
+// flatColumnFieldNames returns the names of the fields of the columns of a
+// flattened table. Column names are unique within a module, but a column may
+// still be named like the table, like a field or method of models.TableNode or
+// like the CellOid method of --cell-oid, which it would shadow, so those get a
+// Column suffix.
+func flatColumnFieldNames(table gosmi.SmiNode, columns []gosmi.SmiNode) []string {
+	taken := map[string]bool{"TableNode": true, formatNodeName(table.Name): true, "CellOid": emitCellOid}
+	tableNodeType := reflect.TypeOf(models.TableNode{})
+	for i := 0; i < tableNodeType.NumMethod(); i++ {
+		taken[tableNodeType.Method(i).Name] = true
+	}
+	var addFields func(t reflect.Type)
+	addFields = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			taken[field.Name] = true
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				addFields(field.Type)
+			}
+		}
+	}
+	addFields(tableNodeType)
+
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = formatNodeName(column.Name)
+		if taken[names[i]] {
+			names[i] += "Column"
+		}
+	}
+	return names
+}
+
+// generateTableType emits the type of a table of its own, see
+// tableTypeColumns, which embeds the table node. With --flatten-tables, it has
+// a field per column, and with --cell-oid a CellOid method building the OIDs
+// of the cells of the table.
+func generateTableType(buf io.Writer, shared *sharedDecls, moduleName string, table gosmi.SmiNode, columns []gosmi.SmiNode) {
+	typeName := tableTypeName(shared, moduleName, table.Name)
+	if flattenTables {
+		fmt.Fprintf(buf, "// %s is %s with direct access to its columns.\n", typeName, table.Name)
+	} else {
+		fmt.Fprintf(buf, "// %s is %s with the methods of its cells.\n", typeName, table.Name)
+	}
+	fmt.Fprintf(buf, "type %s struct {\n", typeName)
+	fmt.Fprintf(buf, "\tmodels.TableNode\n")
+	for _, fieldName := range flatColumnFieldNames(table, columns) {
+		fmt.Fprintf(buf, "\t%s\tmodels.ColumnNode\n", fieldName)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	if emitCellOid {
+		fmt.Fprintf(buf, "// CellOid returns the OID of the cell in column of %s at the row with the\n", table.Name)
+		fmt.Fprintf(buf, "// given index values, encoded according to the index columns by EncodeIndex.\n")
+		fmt.Fprintf(buf, "func (t %s) CellOid(column models.ColumnNode, index ...interface{}) (models.Oid, error) {\n", typeName)
+		fmt.Fprintf(buf, "\tsuffix, err := EncodeIndex(t.Row.Index, t.Row.Implied, index...)\n")
+		fmt.Fprintf(buf, "\tif err != nil {\n")
+		fmt.Fprintf(buf, "\t\treturn nil, err\n")
+		fmt.Fprintf(buf, "\t}\n")
+		fmt.Fprintf(buf, "\toid := make(models.Oid, 0, len(column.Oid)+len(suffix))\n")
+		fmt.Fprintf(buf, "\toid = append(oid, column.Oid...)\n")
+		fmt.Fprintf(buf, "\treturn append(oid, suffix...), nil\n")
+		fmt.Fprintf(buf, "}\n\n")
+	}
+}
+
+// tableTypeLiteral returns the value of the field of a table of its own, see
+// tableTypeColumns, in the module var, which is a func building it with
+// --lazy, so the nodes are still only initialized once they are used.
+func tableTypeLiteral(shared *sharedDecls, moduleName string, table gosmi.SmiNode, columns []gosmi.SmiNode) string {
+	var b strings.Builder
+	typeName := tableTypeName(shared, moduleName, table.Name)
+	fmt.Fprintf(&b, "%s{\n", typeName)
+	fmt.Fprintf(&b, "\t\tTableNode: %s,\n", shared.nodeRef(moduleName, table.Name))
+	for i, fieldName := range flatColumnFieldNames(table, columns) {
+		fmt.Fprintf(&b, "\t\t%s: %s,\n", fieldName, shared.nodeRef(moduleName, columns[i].Name))
+	}
+	fmt.Fprintf(&b, "\t}")
+	if lazyNodes {
+		return fmt.Sprintf("func() %s {\n\t\treturn %s\n\t}", typeName, b.String())
+	}
+	return b.String()
+}
+
+// generateKindAccessors emits methods returning the scalars and the tables of
+// module, in the order of their OIDs. Like the lookup, a method is prefixed if
+// a node takes its name.
+func generateKindAccessors(buf io.Writer, imports imports, module gosmi.SmiModule, nodes []gosmi.SmiNode, shared *sharedDecls) {
+	formattedModuleName := formatModuleName(module.Name)
+	for _, kind := range []struct {
+		kind       types.NodeKind
+		methodName string
+		what       string
+	}{{types.NodeScalar, "Scalars", "scalars"}, {types.NodeTable, "Tables", "tables"}} {
+		methodName := accessorName(kind.methodName, "Get", nodes)
+
+		imports.add(modelsImport)
+		typeName := nodeTypeName(kind.kind)
+		fmt.Fprintf(buf, "// %s returns the %s of %s.\n", methodName, kind.what, module.Name)
+		fmt.Fprintf(buf, "func (%sModule) %s() []%s {\n", formattedModuleName, methodName, typeName)
+		fmt.Fprintf(buf, "\treturn []%s{\n", typeName)
+		for _, node := range nodes {
+			if node.Kind == kind.kind {
+				fmt.Fprintf(buf, "\t\t%s,\n", shared.nodeRef(module.Name, node.Name))
+			}
+		}
+		fmt.Fprintf(buf, "\t}\n")
+		fmt.Fprintf(buf, "}\n\n")
+	}
+}
+
+// accessorName returns the name of a method of the module struct, which is
+// name unless one of nodes has a field of that name. Then name is prefixed
+// with prefix until it no longer collides, e.g. GetGetScalars for a module
+// with the nodes scalars and getScalars.
+func accessorName(name string, prefix string, nodes []gosmi.SmiNode) string {
+	fieldNames := make(map[string]bool)
+	for _, node := range nodes {
+		if node.Kind&allowedNodeKinds > 0 {
+			fieldNames[formatNodeName(node.Name)] = true
+		}
+	}
+	for fieldNames[name] {
+		name = prefix + name
+	}
+	return name
+}
+
+// generateModuleInfo emits the ORGANIZATION, CONTACT-INFO and revisions of
+// module, listed newest first as in the module itself.
+func generateModuleInfo(buf io.Writer, imports imports, shared *sharedDecls, module gosmi.SmiModule) {
+	formattedModuleName := formatModuleName(module.Name)
+	revisions := module.GetRevisions()
+
+	fmt.Fprintf(buf, "// %sModuleInfo holds the metadata of %s.\n", formattedModuleName, module.Name)
+	fmt.Fprintf(buf, "var %sModuleInfo = ModuleInfo{\n", formattedModuleName)
+	var lastUpdated time.Time
+	for _, revision := range revisions {
+		if revision.Date.After(lastUpdated) {
+			lastUpdated = revision.Date
+		}
+	}
+	if !lastUpdated.IsZero() {
+		shared.lastUpdated = true
+		imports.add("time")
+		fmt.Fprintf(buf, "\tLastUpdated: %s,\n", formatTime(lastUpdated))
+	}
+	fmt.Fprintf(buf, "\tOrganization: %q,\n", normalizeEncoding([]byte(module.Organization)))
+	fmt.Fprintf(buf, "\tContactInfo: %q,\n", normalizeEncoding([]byte(module.ContactInfo)))
+	if len(revisions) > 0 {
+		imports.add(modelsImport)
+		fmt.Fprintf(buf, "\tRevisions: []models.Revision{\n")
+		for _, revision := range revisions {
+			fmt.Fprintf(buf, "\t\t{Date: %s, Description: %q},\n", formatTime(revision.Date), normalizeEncoding([]byte(revision.Description)))
+		}
+		fmt.Fprintf(buf, "\t},\n")
+	}
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// formatTime returns an expression for t in UTC, which is how the dates of
+// revisions are written.
+func formatTime(t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf("time.Date(%d, time.%s, %d, %d, %d, 0, 0, time.UTC)", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute())
+}
+
+// moduleRootOid returns the OID module is rooted at, which is the OID of its
+// MODULE-IDENTITY, as reported by identity. For SMIv1 modules, which don't
+// have one, it is the longest common prefix of the OIDs of nodes instead.
+func moduleRootOid(module gosmi.SmiModule, nodes []gosmi.SmiNode) (oid models.Oid, identity bool) {
+	if root, ok := module.GetIdentityNode(); ok {
+		return root.Oid, true
+	}
+
+	for i, node := range nodes {
+		if i == 0 {
+			oid = node.Oid
+			continue
+		}
+		length := 0
+		for length < len(oid) && length < len(node.Oid) && oid[length] == node.Oid[length] {
+			length++
+		}
+		oid = oid[:length]
+	}
+	return oid, false
+}
+
+// instanceOid returns the OID a node is generated with, formatted and with its
+// length. Scalars are instantiated with .0, except for accessible-for-notify
+// ones, which only ever appear in notifications and are never polled.
+func instanceOid(node gosmi.SmiNode) (oid models.Oid, formatted string, length int) {
+	oid, formatted, length = node.Oid, node.RenderNumeric(), node.OidLen
+	if node.Kind == types.NodeScalar && node.Access != types.AccessNotify {
+		oid = append(oid[:len(oid):len(oid)], 0)
+		formatted += ".0"
+		length++
+	}
+	return
+}
+
+// generateExternalStubs emits the vars of nodes referenced from generated
+// modules but defined by modules that aren't generated, with just their name,
+// OID and type.
+func generateExternalStubs(buf io.Writer, imports imports, shared *sharedDecls) {
+	keys := make([]nodeKey, 0, len(shared.external))
+	for key := range shared.external {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].module != keys[j].module {
+			return keys[i].module < keys[j].module
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	for _, key := range keys {
+		node := shared.external[key]
+		varName := shared.nodeVarName(key.module, key.name)
+		oid, oidFormatted, oidLen := instanceOid(node)
+
+		fmt.Fprintf(buf, "// %s is a stub for %s::%s, which is not generated.\n", varName, key.module, key.name)
+		openNodeVar(buf, imports, varName, node.Kind, oidFormatted)
+		if node.Kind&types.NodeColumn > 0 {
+			fmt.Fprintf(buf, "\tScalarNode: models.ScalarNode{\n")
+		}
+		fmt.Fprintf(buf, "\tBaseNode: models.BaseNode{\n")
+		fmt.Fprintf(buf, "\t\tName: %q,\n", node.Name)
+		fmt.Fprintf(buf, "\t\tOid: %#v,\n", oid)
+		fmt.Fprintf(buf, "\t\tOidFormatted: %q,\n", oidFormatted)
+		fmt.Fprintf(buf, "\t\tOidLen: %d,\n", oidLen)
+		fmt.Fprintf(buf, "\t},\n")
+		if node.Type != nil {
+			generateTypeBlock(buf, node.Type, "")
+		}
+		if node.Kind&types.NodeColumn > 0 {
+			fmt.Fprintf(buf, "},\n")
+		}
+		closeNodeVar(buf, varName)
+		fmt.Fprintf(buf, "\n")
+	}
+}
+
+// oidSliceElementType returns the element type of the OID slices emitted for
+// --oid-type.
+func oidSliceElementType() string {
+	if oidType == "gosnmp" {
+		return "uint32"
+	}
+	return oidType
+}
+
+func formatSubIDs(oid models.Oid) string {
+	subIDs := make([]string, len(oid))
+	for i, subID := range oid {
+		subIDs[i] = fmt.Sprint(subID)
+	}
+	return strings.Join(subIDs, ", ")
+}
+
+// nodeID derives a stable ID for the node at oid in the given module. It
+// deliberately leaves out the node name, so the ID survives renames, and uses
+// the OID as registered, without the .0 of scalar instances.
+func nodeID(moduleName string, oid models.Oid) string {
+	sum := sha256.Sum256([]byte(moduleName + "::" + oid.String()))
+	return hex.EncodeToString(sum[:8])
+}
 
-		for i, arg := range args {
-			moduleName, err := gosmi.LoadModule(arg)
-			if err != nil {
-				return errors.Wrapf(err, "Loading module %s", arg)
-			}
+// typeDefinition returns the definition of the named type of node. libsmi
+// merges the UNITS and DISPLAY-HINT of an object into its type, so a node that
+// overrides either is reported, as its type can't be shared then.
+func typeDefinition(node gosmi.SmiNode) (definition *models.Type, overridden bool) {
+	if node.SmiType == nil {
+		return node.Type, false
+	}
 
-			module, err := gosmi.GetModule(moduleName)
-			if err != nil {
-				return errors.Wrapf(err, "Getting module %s", moduleName)
-			}
+	smiType, err := gosmi.GetType(node.Type.Name, node.SmiType.GetModule())
+	if err != nil {
+		return node.Type, false
+	}
 
-			fileBuf := &bytes.Buffer{}
-			if out == nil || i == 0 {
-				fmt.Fprintf(fileBuf, fileHeader, packageName)
-			}
+	return &smiType.Type, smiType.Units != node.Type.Units || smiType.Format != node.Type.Format
+}
 
-			generateMibFile(module, fileBuf, typesMap)
+// generateUnitWrapper emits a type for the values of node, named typeName,
+// which renders them followed by the UNITS of node.
+func generateUnitWrapper(buf io.Writer, imports imports, typeName string, node gosmi.SmiNode) {
+	valueType, ok := nativeFieldTypes[node.Type.BaseType]
+	if !ok {
+		return
+	}
+	verb := "%v"
+	if valueType == "[]byte" {
+		verb = "%s"
+	}
 
-			outFile := out
-			if outFile == nil {
-				filename := path.Join(outDir, strings.ToLower(module.Name)+".go")
-				outFile, err = os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
-				if err != nil {
-					return errors.Wrapf(err, "Opening file %s", filename)
-				}
-				defer outFile.Close()
-				log.Printf("Outputting to %s\n", filename)
-			}
+	imports.add("fmt")
+	fmt.Fprintf(buf, "// %s is a value of %s in %s.\n", typeName, node.Name, node.Type.Units)
+	fmt.Fprintf(buf, "type %s %s\n\n", typeName, valueType)
+	fmt.Fprintf(buf, "// Units returns the UNITS of %s.\n", node.Name)
+	fmt.Fprintf(buf, "func (%s) Units() string {\n", typeName)
+	fmt.Fprintf(buf, "\treturn %q\n", node.Type.Units)
+	fmt.Fprintf(buf, "}\n\n")
+	fmt.Fprintf(buf, "// String returns v followed by its units.\n")
+	fmt.Fprintf(buf, "func (v %s) String() string {\n", typeName)
+	fmt.Fprintf(buf, "\treturn fmt.Sprintf(%q, %s(v), %q)\n", verb+" %s", valueType, node.Type.Units)
+	fmt.Fprintf(buf, "}\n\n")
+}
 
-			err = writeGoFile(outFile, fileBuf.Bytes())
-			if err != nil {
-				return errors.Wrap(err, "Writing module Go file")
+// generateNativeStruct emits a struct named typeName with a field of the
+// native Go type of each of nodes. With --smi-types, counters, gauges and time
+// ticks get the types declared for them instead.
+func generateNativeStruct(buf io.Writer, typeName string, nodes []gosmi.SmiNode) {
+	fmt.Fprintf(buf, "type %s struct {\n", typeName)
+	for _, node := range nodes {
+		fieldType := "[]byte"
+		if node.Type != nil {
+			if nativeType, ok := nativeFieldTypes[node.Type.BaseType]; ok {
+				fieldType = nativeType
+			}
+		}
+		if smiTypes {
+			if smiType, ok := smiApplicationType(node); ok {
+				fieldType = smiType
 			}
 		}
+		fmt.Fprintf(buf, "\t%s %s\n", formatNodeName(node.Name), fieldType)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+}
 
-		typesBuf := &bytes.Buffer{}
-		if out == nil {
-			fmt.Fprintf(typesBuf, fileHeader, packageName)
+// generateIndexStruct emits a comparable struct with a typed field per index
+// column of table, for use as a map key, along with its Encode method and
+// Decode func converting it from and to the suffix of the OIDs of the cells of
+// a row. Object identifiers are kept in dotted notation.
+func generateIndexStruct(buf io.Writer, imports imports, table gosmi.SmiNode) {
+	imports.add(modelsImport, "fmt")
+	typeName := formatNodeName(table.Name) + "Index"
+	fmt.Fprintf(buf, "// %s is the INDEX of %s.\n", typeName, table.Name)
+	fmt.Fprintf(buf, "type %s struct {\n", typeName)
+	indices, _, _ := rowIndex(table.GetRow())
+	for _, index := range indices {
+		fieldType, ok := indexFieldTypes[index.Type.BaseType]
+		if !ok {
+			fieldType = "string"
 		}
+		fmt.Fprintf(buf, "\t%s %s\n", formatNodeName(index.Name), fieldType)
+	}
+	fmt.Fprintf(buf, "}\n\n")
 
-		keys := make([]string, 0, len(typesMap))
-		for k := range typesMap {
-			keys = append(keys, k)
+	implied := rowImplied(table.GetRow())
+	fmt.Fprintf(buf, "// Encode returns the suffix of the OIDs of the cells of the row at index.\n")
+	fmt.Fprintf(buf, "func (index %s) Encode() (models.Oid, error) {\n", typeName)
+	fmt.Fprintf(buf, "\tvar oid models.Oid\n")
+	for _, index := range indices {
+		if indexValueKind(index.Type) == types.BaseTypeObjectIdentifier {
+			fmt.Fprintf(buf, "\tvar err error\n")
+			break
 		}
-		sort.Strings(keys)
-		for _, key := range keys {
-			generateTypeBlock(typesBuf, typesMap[key], true)
+	}
+	for i, index := range indices {
+		fieldName := formatNodeName(index.Name)
+		size := indexValueSize(index.Type, implied && i == len(indices)-1)
+		switch indexValueKind(index.Type) {
+		case types.BaseTypeOctetString:
+			fmt.Fprintf(buf, "\toid = EncodeIndexOctets(oid, index.%s, %t)\n", fieldName, size < 0)
+		case types.BaseTypeObjectIdentifier:
+			fmt.Fprintf(buf, "\toid, err = EncodeIndexOid(oid, index.%s, %t)\n", fieldName, size < 0)
+			fmt.Fprintf(buf, "\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+		default:
+			fmt.Fprintf(buf, "\toid = append(oid, uint32(index.%s))\n", fieldName)
 		}
+	}
+	fmt.Fprintf(buf, "\treturn oid, nil\n")
+	fmt.Fprintf(buf, "}\n\n")
 
-		outFile := out
-		if outFile == nil {
-			filename := "types.go"
-			outFile, err = os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
-			if err != nil {
-				return errors.Wrapf(err, "Opening file %s", filename)
-			}
-			defer outFile.Close()
-			log.Printf("Outputting to %s\n", filename)
+	fmt.Fprintf(buf, "// Decode%s returns the index of the row of the cells whose OIDs end in\n", typeName)
+	fmt.Fprintf(buf, "// suffix.\n")
+	fmt.Fprintf(buf, "func Decode%s(suffix models.Oid) (%s, error) {\n", typeName, typeName)
+	fmt.Fprintf(buf, "\tvar index %s\n", typeName)
+	if len(indices) > 0 {
+		fmt.Fprintf(buf, "\tvar value models.Oid\n")
+		fmt.Fprintf(buf, "\tvar err error\n")
+	}
+	for i, index := range indices {
+		fieldName := formatNodeName(index.Name)
+		fieldType, ok := indexFieldTypes[index.Type.BaseType]
+		if !ok {
+			fieldType = "string"
 		}
-
-		err = writeGoFile(outFile, typesBuf.Bytes())
-		if err != nil {
-			return errors.Wrap(err, "Writing types Go file")
+		impliedLast := implied && i == len(indices)-1
+		size := fmt.Sprint(indexValueSize(index.Type, impliedLast))
+		if impliedLast && indexValueKind(index.Type) != types.BaseTypeInteger32 {
+			size = "len(suffix)"
+		}
+		fmt.Fprintf(buf, "\tvalue, suffix, err = DecodeIndexValue(suffix, %s)\n", size)
+		fmt.Fprintf(buf, "\tif err != nil {\n\t\treturn index, err\n\t}\n")
+		switch indexValueKind(index.Type) {
+		case types.BaseTypeOctetString:
+			fmt.Fprintf(buf, "\tindex.%s, err = DecodeIndexOctets(value)\n", fieldName)
+			fmt.Fprintf(buf, "\tif err != nil {\n\t\treturn index, err\n\t}\n")
+		case types.BaseTypeObjectIdentifier:
+			fmt.Fprintf(buf, "\tindex.%s = value.String()\n", fieldName)
+		default:
+			fmt.Fprintf(buf, "\tindex.%s = %s(value[0])\n", fieldName, fieldType)
 		}
-
-		return nil
-	},
-}
-
-func formatModuleName(moduleName string) (formattedName string) {
-	parts := strings.Split(moduleName, "-")
-	for _, part := range parts {
-		formattedName += strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
 	}
-	return
+	fmt.Fprintf(buf, "\tif len(suffix) > 0 {\n")
+	fmt.Fprintf(buf, "\t\treturn index, fmt.Errorf(\"Index has %%d sub-identifiers too many\", len(suffix))\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\treturn index, nil\n")
+	fmt.Fprintf(buf, "}\n")
 }
 
-func formatComment(comment string) string {
-	return commentReplacer.Replace(comment)
+// indexValueKind returns how an index value of type t is encoded: as an
+// integer, an object identifier or, like any other base type the index struct
+// keeps as a string, an octet string.
+func indexValueKind(t *models.Type) types.BaseType {
+	switch t.BaseType {
+	case types.BaseTypeInteger32, types.BaseTypeUnsigned32, types.BaseTypeInteger64, types.BaseTypeUnsigned64, types.BaseTypeEnum:
+		return types.BaseTypeInteger32
+	case types.BaseTypeObjectIdentifier:
+		return types.BaseTypeObjectIdentifier
+	}
+	return types.BaseTypeOctetString
 }
 
-func formatNodeName(nodeName string) (formattedName string) {
-	return strings.ToUpper(nodeName[:1]) + nodeName[1:]
+// indexValueSize returns the number of sub-identifiers of an index value of
+// type t, or -1 if it is preceded by its length. Octet strings of a fixed size
+// and the last value of an IMPLIED index go without their length, which leaves
+// the latter with the rest of the OID, and 0 here.
+func indexValueSize(t *models.Type, impliedLast bool) int {
+	kind := indexValueKind(t)
+	if kind == types.BaseTypeInteger32 {
+		return 1
+	}
+	if impliedLast {
+		return 0
+	}
+	if kind == types.BaseTypeOctetString && len(t.Ranges) == 1 && t.Ranges[0].MinValue == t.Ranges[0].MaxValue {
+		return int(t.Ranges[0].MinValue)
+	}
+	return -1
 }
 
-func formatNodeVarName(nodeName string) (formattedName string) {
-	return strings.ToLower(nodeName[:1]) + nodeName[1:] + "Node"
+// nextSiblingOid returns the first OID following the subtree rooted at oid,
+// which is the OID of its next sibling. If the last sub-identifier can't be
+// incremented any further, the parent's next sibling is used.
+func nextSiblingOid(oid models.Oid) models.Oid {
+	for i := len(oid) - 1; i >= 0; i-- {
+		if oid[i] < math.MaxUint32 {
+			next := make(models.Oid, i+1)
+			copy(next, oid)
+			next[i]++
+			return next
+		}
+	}
+	return nil
 }
 
-func generateMibFile(module gosmi.SmiModule, buf io.Writer, typesMap map[string]*models.Type) {
-	formattedModuleName := formatModuleName(module.Name)
-	nodes := module.GetNodes()
+func generateTablesMap(buf io.Writer, shared *sharedDecls) {
+	entries := make(map[string]string, len(shared.tables))
+	keys := make([]string, 0, len(shared.tables))
+	for _, table := range shared.tables {
+		row := table.GetRow()
+		moduleName := table.GetModule().Name
+		key := row.RenderNumeric()
+		entries[key] = fmt.Sprintf("{Table: %s, Columns: %s.Columns, Index: %s.Index}",
+			shared.nodeRef(moduleName, table.Name),
+			shared.nodeRef(moduleName, row.Name),
+			shared.nodeRef(moduleName, row.Name),
+		)
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-	fmt.Fprintf(buf, "/*\n%s\n*/\n", formatComment(module.Description))
+	fmt.Fprintf(buf, "// TableInfo holds the columns and index of a table.\n")
+	fmt.Fprintf(buf, "type TableInfo struct {\n")
+	fmt.Fprintf(buf, "\tTable models.TableNode\n")
+	fmt.Fprintf(buf, "\tColumns []models.ColumnNode\n")
+	fmt.Fprintf(buf, "\tIndex []models.ColumnNode\n")
+	fmt.Fprintf(buf, "}\n\n")
 
-	fmt.Fprintf(buf, "type %sModule struct {\n", formattedModuleName)
-	for _, node := range nodes {
-		if node.Kind&allowedNodeKinds > 0 {
-			fmt.Fprintf(buf, "\t%s\tmodels.%sNode\n", formatNodeName(node.Name), node.Kind)
-		}
+	fmt.Fprintf(buf, "// Tables maps the OID of each table entry to its table.\n")
+	fmt.Fprintf(buf, "var Tables = map[string]TableInfo{\n")
+	for _, key := range keys {
+		fmt.Fprintf(buf, "\t%q: %s,\n", key, entries[key])
 	}
 	fmt.Fprintf(buf, "}\n\n")
+}
 
-	fmt.Fprintf(buf, "var %s = %sModule {\n", formattedModuleName, formattedModuleName)
-	for _, node := range nodes {
-		if node.Kind&allowedNodeKinds > 0 {
-			fmt.Fprintf(buf, "\t%s:\t%s,\n", formatNodeName(node.Name), formatNodeVarName(node.Name))
-		}
+// generateNotificationsMap emits the notifications DecodeNotification looks up,
+// keyed by their OID.
+func generateNotificationsMap(buf io.Writer, shared *sharedDecls) {
+	entries := make(map[string]string, len(shared.notifications))
+	keys := make([]string, 0, len(shared.notifications))
+	for _, notification := range shared.notifications {
+		key := notification.RenderNumeric()
+		entries[key] = shared.nodeRef(notification.GetModule().Name, notification.Name)
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(buf, "var notifications = map[string]models.NotificationNode{\n")
+	for _, key := range keys {
+		fmt.Fprintf(buf, "\t%q: %s,\n", key, entries[key])
 	}
 	fmt.Fprintf(buf, "}\n\n")
+}
 
-	for _, node := range nodes {
-		if node.Kind&allowedNodeKinds == 0 {
-			continue
-		}
+// generateModulesMap emits a map of the module structs of modules, keyed by
+// module name.
+func generateModulesMap(buf io.Writer, modules []gosmi.SmiModule) {
+	names := make([]string, len(modules))
+	for i, module := range modules {
+		names[i] = module.Name
+	}
+	sort.Strings(names)
 
-		fmt.Fprintf(buf, "/*\n%s\n*/\n", formatComment(node.Description))
-		fmt.Fprintf(buf, "var %s = models.%sNode{\n", formatNodeVarName(node.Name), node.Kind)
+	fmt.Fprintf(buf, "// Modules maps the names of the generated modules to their module structs.\n")
+	fmt.Fprintf(buf, "var Modules = map[string]interface{}{\n")
+	for _, name := range names {
+		fmt.Fprintf(buf, "\t%q: %s,\n", name, formatModuleName(name))
+	}
+	fmt.Fprintf(buf, "}\n\n")
+}
 
-		if node.Kind&types.NodeColumn > 0 {
-			fmt.Fprintf(buf, "\tScalarNode: models.ScalarNode{\n")
-		}
+// generateDescriptions emits the descriptions of the nodes, and of the modules
+// by their MODULE-IDENTITY, keyed by OID for lookup at runtime.
+func generateDescriptions(buf io.Writer, shared *sharedDecls) {
+	keys := make([]string, 0, len(shared.descriptions))
+	for key := range shared.descriptions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-		fmt.Fprintf(buf, "\tBaseNode: models.BaseNode{\n")
-		fmt.Fprintf(buf, "\t\tName: %q,\n", node.Name)
-		oid := node.Oid
-		oidFormatted := node.RenderNumeric()
-		oidLen := node.OidLen
-		if node.Kind == types.NodeScalar {
-			oid = append(oid, 0)
-			oidFormatted += ".0"
-			oidLen++
-		}
-		fmt.Fprintf(buf, "\t\tOid: %#v,\n", oid)
-		fmt.Fprintf(buf, "\t\tOidFormatted: %q,\n", oidFormatted)
-		fmt.Fprintf(buf, "\t\tOidLen: %d,\n", oidLen)
-		fmt.Fprintf(buf, "\t},\n")
+	fmt.Fprintf(buf, "// Descriptions maps the OIDs of nodes and modules to their descriptions.\n")
+	fmt.Fprintf(buf, "var Descriptions = map[string]string{\n")
+	for _, key := range keys {
+		fmt.Fprintf(buf, "\t%q: %q,\n", key, normalizeEncoding([]byte(shared.descriptions[key])))
+	}
+	fmt.Fprintf(buf, "}\n")
+}
 
-		if node.Kind&(types.NodeColumn|types.NodeScalar) > 0 {
-			switch node.Type.Name {
-			case "Integer32", "OctetString", "ObjectIdentifier", "Unsigned32", "Integer64", "Unsigned64", "Enumeration", "Bits":
-				generateTypeBlock(buf, node.Type, false)
-			default:
-				if _, ok := typesMap[node.Type.Name]; !ok {
-					typesMap[node.Type.Name] = node.Type
-				}
-				fmt.Fprintf(buf, "\tType: %sType,\n", formatNodeName(node.Type.Name))
-			}
-		} else if node.Kind == types.NodeTable {
-			fmt.Fprintf(buf, "\tRow: %s,\n", formatNodeVarName(node.GetRow().Name))
-		} else if node.Kind == types.NodeRow {
-			fmt.Fprintf(buf, "\tColumns: []models.ColumnNode{\n")
-			_, columnOrder := node.GetColumns()
-			for _, column := range columnOrder {
-				fmt.Fprintf(buf, "\t\t%s,\n", formatNodeVarName(column))
-			}
-			fmt.Fprintf(buf, "\t},\n")
-			fmt.Fprintf(buf, "\tIndex: []models.ColumnNode{\n")
-			indices := node.GetIndex()
-			for _, index := range indices {
-				fmt.Fprintf(buf, "\t\t%s,\n", formatNodeVarName(index.Name))
-			}
-			fmt.Fprintf(buf, "\t},\n")
-		} else if node.Kind == types.NodeNotification {
-			objects := node.GetNotificationObjects()
-			fmt.Fprintf(buf, "\tObjects: []models.ScalarNode{\n")
-			for _, object := range objects {
-				if object.Kind == types.NodeScalar {
-					fmt.Fprintf(buf, "\t\t%s,\n", formatNodeVarName(object.Name))
-				} else {
-					fmt.Fprintf(buf, "\t\t%s.ScalarNode,\n", formatNodeVarName(object.Name))
-				}
-			}
-			fmt.Fprintf(buf, "\t},\n")
-		}
+// generateOidIndex emits the nodes of all generated modules keyed by the OID
+// they are generated with, which is the instance OID for scalars.
+func generateOidIndex(buf io.Writer, shared *sharedDecls) {
+	keys := make([]string, 0, len(shared.oidIndex))
+	for key := range shared.oidIndex {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-		if node.Kind&types.NodeColumn > 0 {
-			fmt.Fprintf(buf, "},\n")
-		}
+	fmt.Fprintf(buf, "// OidIndex maps the OIDs of all nodes to the nodes.\n")
+	fmt.Fprintf(buf, "var OidIndex = map[string]models.BaseNode{\n")
+	for _, key := range keys {
+		fmt.Fprintf(buf, "\t%q: %s,\n", key, shared.oidIndex[key])
+	}
+	fmt.Fprintf(buf, "}\n")
+}
 
-		fmt.Fprintf(buf, "}\n")
+// generateEnumLabels emits the labels of an enumeration sorted alphabetically
+// for display purposes, as the values map has no order of its own.
+func generateEnumLabels(buf io.Writer, varName string, enum *models.Enum) {
+	labels := make([]string, 0, len(enum.Values))
+	for _, label := range enum.Values {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	fmt.Fprintf(buf, "var %sLabels = []string{\n", varName)
+	for _, label := range labels {
+		fmt.Fprintf(buf, "\t%q,\n", label)
 	}
+	fmt.Fprintf(buf, "}\n\n")
 }
 
-func generateTypeBlock(buf io.Writer, t *models.Type, asVar bool) {
-	if asVar {
-		fmt.Fprintf(buf, "var %sType = models.Type{\n", formatNodeName(t.Name))
+// generateTypeBlock emits t as the var of the shared type typeName, or as the
+// Type field of a node if typeName is empty.
+func generateTypeBlock(buf io.Writer, t *models.Type, typeName string) {
+	if typeName != "" {
+		fmt.Fprintf(buf, "var %s = models.Type{\n", formatTypeVarName(typeName))
 	} else {
 		fmt.Fprintf(buf, "Type: models.Type{\n")
 	}
+	generateTypeFields(buf, t)
+	if typeName != "" {
+		fmt.Fprintf(buf, "}\n\n")
+	} else {
+		fmt.Fprintf(buf, "},\n")
+	}
+}
+
+// generateTypeFields emits the fields of a models.Type literal for t.
+func generateTypeFields(buf io.Writer, t *models.Type) {
 	fmt.Fprintf(buf, "\tBaseType: types.BaseType%s,\n", t.BaseType)
 	if t.Enum != nil {
 		fmt.Fprintf(buf, "\tEnum: &models.Enum{\n")
@@ -296,31 +3146,405 @@ func generateTypeBlock(buf io.Writer, t *models.Type, asVar bool) {
 	if len(t.Ranges) > 0 {
 		fmt.Fprintf(buf, "\tRanges: []models.Range{\n")
 		for _, typeRange := range t.Ranges {
-			fmt.Fprintf(buf, "\t\tmodels.Range{BaseType: types.BaseType%s, MinValue: %#v, MaxValue: %#v},\n",
+			fmt.Fprintf(buf, "\t\tmodels.Range{BaseType: types.BaseType%s, MinValue: %s, MaxValue: %s",
 				typeRange.BaseType,
-				typeRange.MinValue,
-				typeRange.MaxValue,
+				formatRangeValue(typeRange.BaseType, typeRange.MinValue),
+				formatRangeValue(typeRange.BaseType, typeRange.MaxValue),
 			)
+			// Only the standalone Range can tell size ranges apart, the
+			// gosmi one is marked by a comment instead.
+			switch {
+			case !isSizeConstrained(t):
+				fmt.Fprintf(buf, "},\n")
+			case standalone:
+				fmt.Fprintf(buf, ", SizeConstraint: true},\n")
+			default:
+				fmt.Fprintf(buf, "}, // SIZE\n")
+			}
 		}
 		fmt.Fprintf(buf, "\t},\n")
 	}
 	if t.Units != "" {
 		fmt.Fprintf(buf, "\tUnits: %q,\n", t.Units)
 	}
-	if asVar {
+}
+
+// generateEnumType emits a type named typeName for the values of enum. With
+// --enum-consts, it gets a constant per value, named after the type and the
+// label of the value, with --enum-strings a String method returning the label
+// of a value and with --enum-parse a Parse function doing the reverse.
+func generateEnumType(buf io.Writer, imports imports, typeName string, enum *models.Enum) {
+	fmt.Fprintf(buf, "// %s is an enumerated value.\n", typeName)
+	fmt.Fprintf(buf, "type %s int64\n\n", typeName)
+
+	if emitEnumConsts {
+		fmt.Fprintf(buf, "const (\n")
+		seen := make(map[string]bool)
+		for _, key := range enum.Values.Keys() {
+			name := enumConstName(typeName, enum.Values[int64(key)], key, seen)
+			fmt.Fprintf(buf, "\t%s %s = %d\n", name, typeName, key)
+		}
+		fmt.Fprintf(buf, ")\n\n")
+	}
+
+	if enumStrings {
+		// The value is converted for the fallback, as formatting it as is
+		// would call String again.
+		imports.add("fmt")
+		fmt.Fprintf(buf, "// String returns the label of e, or unknown(e) for values %s doesn't define.\n", typeName)
+		fmt.Fprintf(buf, "func (e %s) String() string {\n", typeName)
+		fmt.Fprintf(buf, "\tswitch e {\n")
+		for _, key := range enum.Values.Keys() {
+			fmt.Fprintf(buf, "\tcase %d:\n", key)
+			fmt.Fprintf(buf, "\t\treturn %q\n", enum.Values[int64(key)])
+		}
+		fmt.Fprintf(buf, "\t}\n")
+		fmt.Fprintf(buf, "\treturn fmt.Sprintf(\"unknown(%%d)\", int64(e))\n")
+		fmt.Fprintf(buf, "}\n\n")
+	}
+
+	if enumParse {
+		generateEnumParse(buf, imports, typeName, enum)
+	}
+}
+
+// generateEnumParse emits a function parsing the labels of enum to values of
+// typeName. With --enum-parse-ci, labels that don't match exactly are matched
+// case-insensitively, and labels differing only in case resolve to the lowest
+// of their values.
+func generateEnumParse(buf io.Writer, imports imports, typeName string, enum *models.Enum) {
+	mapName := lowerFirst(typeName) + "Values"
+	fmt.Fprintf(buf, "var %s = map[string]%s{\n", mapName, typeName)
+	for _, key := range enum.Values.Keys() {
+		fmt.Fprintf(buf, "\t%q: %d,\n", enum.Values[int64(key)], key)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	if enumParseCI {
+		imports.add("strings")
+		fmt.Fprintf(buf, "var %sFolded = map[string]%s{\n", mapName, typeName)
+		seen := make(map[string]bool)
+		for _, key := range enum.Values.Keys() {
+			folded := strings.ToLower(enum.Values[int64(key)])
+			if !seen[folded] {
+				seen[folded] = true
+				fmt.Fprintf(buf, "\t%q: %d,\n", folded, key)
+			}
+		}
 		fmt.Fprintf(buf, "}\n\n")
+	}
+
+	fmt.Fprintf(buf, "// Parse%s returns the value of %s labeled s, and whether there is one.\n", typeName, typeName)
+	fmt.Fprintf(buf, "func Parse%s(s string) (%s, bool) {\n", typeName, typeName)
+	if enumParseCI {
+		fmt.Fprintf(buf, "\tif e, ok := %s[s]; ok {\n", mapName)
+		fmt.Fprintf(buf, "\t\treturn e, true\n")
+		fmt.Fprintf(buf, "\t}\n")
+		fmt.Fprintf(buf, "\te, ok := %sFolded[strings.ToLower(s)]\n", mapName)
 	} else {
-		fmt.Fprintf(buf, "},\n")
+		fmt.Fprintf(buf, "\te, ok := %s[s]\n", mapName)
+	}
+	fmt.Fprintf(buf, "\treturn e, ok\n")
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// generateBitsConsts emits a constant per bit of a BITS type named typeName,
+// holding its position, and a helper testing a bit of an encoded value. Bits
+// are numbered like on the wire, with bit 0 being the most significant bit of
+// the first octet.
+func generateBitsConsts(buf io.Writer, typeName string, enum *models.Enum) {
+	fmt.Fprintf(buf, "// Positions of the bits of %s.\n", typeName)
+	fmt.Fprintf(buf, "const (\n")
+	seen := make(map[string]bool)
+	for _, key := range enum.Values.Keys() {
+		name := enumConstName(typeName+"Bit", enum.Values[int64(key)], key, seen)
+		fmt.Fprintf(buf, "\t%s uint = %d\n", name, key)
+	}
+	fmt.Fprintf(buf, ")\n\n")
+
+	fmt.Fprintf(buf, "// %sHasBit reports whether the bit at position bit is set in value, a %s\n", typeName, typeName)
+	fmt.Fprintf(buf, "// as encoded in an OCTET STRING.\n")
+	fmt.Fprintf(buf, "func %sHasBit(value []byte, bit uint) bool {\n", typeName)
+	fmt.Fprintf(buf, "\ti := bit / 8\n")
+	fmt.Fprintf(buf, "\treturn i < uint(len(value)) && value[i]&(0x80>>(bit%%8)) != 0\n")
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// enumConstName returns the name of the constant for the value key labeled
+// label, prefixed with prefix. Empty labels are named after the value, as are
+// labels that end up with the same name as an earlier one in seen.
+func enumConstName(prefix string, label string, key int, seen map[string]bool) string {
+	name := prefix
+	for _, part := range strings.Split(label, "-") {
+		name += upperFirst(part)
+	}
+	if name == prefix || seen[name] {
+		name += strings.Replace(fmt.Sprint(key), "-", "Minus", 1)
+	}
+	name = sanitizeIdentifier(name)
+	seen[name] = true
+	return name
+}
+
+// imports collects the import paths of the packages a generated file needs.
+type imports map[string]bool
+
+func (i imports) add(importPaths ...string) {
+	for _, importPath := range importPaths {
+		i[importPath] = true
+	}
+}
+
+// referencedImports returns those of imports whose package body references,
+// so a file doesn't import a package that nothing ended up using, which won't
+// compile. Packages are referenced by the last element of their import path.
+// Source that doesn't parse keeps all imports, as formatting reports it.
+func referencedImports(imports imports, body []byte) imports {
+	file, err := parser.ParseFile(token.NewFileSet(), "", append([]byte("package p\n"), body...), 0)
+	if err != nil {
+		return imports
+	}
+
+	unresolved := make(map[string]bool)
+	for _, ident := range file.Unresolved {
+		unresolved[ident.Name] = true
+	}
+	referenced := make(map[string]bool, len(imports))
+	for importPath := range imports {
+		if unresolved[path.Base(importPath)] {
+			referenced[importPath] = true
+		}
+	}
+	return referenced
+}
+
+// fileHeader returns the header of a generated file of package filePackage,
+// importing the standard library packages and the other packages in imports as
+// separate groups. With --build-tag, the file starts with the build constraint,
+// separated from the rest by a blank line as go build requires.
+func fileHeader(filePackage string, imports imports) []byte {
+	var std, other []string
+	for importPath := range imports {
+		// The standalone declarations take the place of these packages.
+		if standalone && (importPath == modelsImport || importPath == typesImport) {
+			continue
+		}
+		if strings.Contains(strings.SplitN(importPath, "/", 2)[0], ".") {
+			other = append(other, importPath)
+		} else {
+			std = append(std, importPath)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	buf := &bytes.Buffer{}
+	if buildTag != "" {
+		fmt.Fprintf(buf, "//go:build %s\n\n", buildTag)
+	}
+	if headerTemplate != nil {
+		// Executing the template can't fail, as loadHeaderTemplate
+		// already executed it with the same data.
+		start := buf.Len()
+		_ = headerTemplate.Execute(buf, headerData{Package: filePackage})
+		if buf.Len() > start && buf.Bytes()[buf.Len()-1] != '\n' {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("\n")
+	} else {
+		fmt.Fprintf(buf, "// Code generated by mib2go. DO NOT EDIT.\n")
+	}
+	fmt.Fprintf(buf, "package %s\n\n", filePackage)
+	if len(std)+len(other) > 0 {
+		fmt.Fprintf(buf, "import (\n")
+		for i, group := range [][]string{std, other} {
+			if i > 0 && len(std) > 0 && len(other) > 0 {
+				fmt.Fprintf(buf, "\n")
+			}
+			for _, importPath := range group {
+				fmt.Fprintf(buf, "\t%q\n", importPath)
+			}
+		}
+		fmt.Fprintf(buf, ")\n\n")
+	}
+	return buf.Bytes()
+}
+
+// headerData is what a --header-file template is executed with.
+type headerData struct {
+	Package string
+}
+
+// loadHeaderTemplate loads the template of the header replacing the built-in
+// one from filename. It is checked to still result in valid Go, and the
+// package name is available as {{.Package}}.
+func loadHeaderTemplate(filename string) (*template.Template, error) {
+	text, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Reading header file %s", filename)
+	}
+
+	headerTemplate, err := template.New(path.Base(filename)).Parse(string(text))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Parsing header file %s", filename)
+	}
+
+	buf := &bytes.Buffer{}
+	err = headerTemplate.Execute(buf, headerData{Package: packageName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Executing header file %s", filename)
+	}
+	_, err = format.Source(append(buf.Bytes(), "\npackage "+packageName+"\n"...))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Header file %s doesn't result in valid Go", filename)
 	}
+	if !strings.Contains(buf.String(), "DO NOT EDIT") {
+		log.Printf("Header file %s doesn't mark files as generated with a \"Code generated ... DO NOT EDIT.\" comment\n", filename)
+	}
+
+	return headerTemplate, nil
+}
+
+// trailingNewlineWriter holds back a trailing newline until more output
+// follows, which drops it from the very end of the output.
+type trailingNewlineWriter struct {
+	w       io.Writer
+	pending bool
+}
+
+func (t *trailingNewlineWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if n == 0 {
+		return 0, nil
+	}
+
+	if t.pending {
+		if _, err := t.w.Write([]byte{'\n'}); err != nil {
+			return 0, err
+		}
+		t.pending = false
+	}
+
+	if p[n-1] == '\n' {
+		p = p[:n-1]
+		t.pending = true
+	}
+
+	if _, err := t.w.Write(p); err != nil {
+		return 0, err
+	}
+	return n, nil
 }
 
 func writeGoFile(out io.Writer, b []byte) error {
+	if formatChunkSize > 0 && len(b) > formatChunkSize {
+		return writeGoFileChunked(out, b)
+	}
+
+	return writeFormattedChunk(out, nil, b)
+}
+
+// writeGoFileChunked formats b in pieces of roughly formatChunkSize bytes, so
+// go/format never has to hold the syntax tree of an enormous file at once.
+// Pieces are only cut after a top-level closing brace outside of a comment,
+// which the generator always writes on a line of its own. The result is
+// identical to formatting the whole file at once.
+func writeGoFileChunked(out io.Writer, b []byte) error {
+	var inComment bool
+	start := 0
+	last := token.ILLEGAL
+	var err error
+	for pos := 0; pos < len(b); {
+		end := bytes.IndexByte(b[pos:], '\n')
+		if end < 0 {
+			end = len(b)
+		} else {
+			end += pos + 1
+		}
+
+		switch line := string(bytes.TrimRight(b[pos:end], "\n")); {
+		case line == "/*":
+			inComment = true
+		case line == "*/":
+			inComment = false
+		case line == "}" && !inComment && end-start >= formatChunkSize:
+			last, err = writeChunk(out, b[start:end], last)
+			if err != nil {
+				return err
+			}
+			start = end
+		}
+		pos = end
+	}
+
+	if start < len(b) {
+		_, err = writeChunk(out, b[start:], last)
+	}
+	return err
+}
+
+// writeChunk formats a piece of a file cut by writeGoFileChunked, following
+// a piece whose last declaration is of the kind prev, or none for the first
+// piece, and returns the kind of its own last declaration. go/format keeps the
+// surrounding whitespace of pieces but the first, so that is normalized the
+// way gofmt separates and ends top-level declarations: by a single blank line
+// if the source has one, the declaration has a doc comment or is of another
+// kind than the one before it, and by a single newline at the end.
+func writeChunk(out io.Writer, chunk []byte, prev token.Token) (token.Token, error) {
+	src, header := chunk, []byte(nil)
+	var prefix []byte
+	if prev != token.ILLEGAL {
+		trimmed := bytes.TrimSpace(chunk)
+		if len(trimmed) == 0 {
+			return prev, nil
+		}
+		if leading := chunk[:len(chunk)-len(bytes.TrimLeft(chunk, " \t\r\n"))]; bytes.Contains(leading, []byte("\n")) {
+			prefix = []byte("\n")
+		}
+		src, header = append(trimmed[:len(trimmed):len(trimmed)], '\n'), []byte("package p\n")
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "", append(header, src...), parser.ParseComments)
+	if err != nil {
+		return prev, errors.Wrap(err, "Generating formatted source")
+	}
+	if len(file.Decls) > 0 {
+		first := file.Decls[0]
+		if prev != token.ILLEGAL && (declToken(first) != prev || declDoc(first) != nil) {
+			prefix = []byte("\n")
+		}
+		prev = declToken(file.Decls[len(file.Decls)-1])
+	}
+
+	return prev, writeFormattedChunk(out, prefix, src)
+}
+
+// declToken returns the keyword decl is declared with.
+func declToken(decl ast.Decl) token.Token {
+	if genDecl, ok := decl.(*ast.GenDecl); ok {
+		return genDecl.Tok
+	}
+	return token.FUNC
+}
+
+// declDoc returns the doc comment of decl, if any.
+func declDoc(decl ast.Decl) *ast.CommentGroup {
+	switch decl := decl.(type) {
+	case *ast.GenDecl:
+		return decl.Doc
+	case *ast.FuncDecl:
+		return decl.Doc
+	}
+	return nil
+}
+
+func writeFormattedChunk(out io.Writer, prefix []byte, b []byte) error {
 	formattedSource, err := format.Source(b)
 	if err != nil {
 		return errors.Wrap(err, "Generating formatted source")
 	}
 
-	_, err = out.Write(formattedSource)
+	_, err = out.Write(append(prefix, formattedSource...))
 	if err != nil {
 		return errors.Wrap(err, "Writing file")
 	}
@@ -339,9 +3563,84 @@ func init() {
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
-	flags := generateCmd.Flags()
+	addGenerateFlags(generateCmd.Flags())
+}
+
+// addGenerateFlags adds the flags of the generate command to flags, which
+// resets the options they are bound to to their defaults.
+func addGenerateFlags(flags *pflag.FlagSet) {
 	flags.StringVarP(&outDir, "dir", "d", ".", "Output directory")
 	flags.StringVarP(&outFilename, "output", "o", "", "Output filename, use - for stdout")
 	flags.StringVarP(&packageName, "package", "p", "mibs", "The package for the generated file")
 	flags.StringSliceVarP(&paths, "path", "M", []string{}, "Path(s) to add to MIB search path")
+	flags.StringSliceVar(&overrides, "override-path", []string{}, "Path(s) searched for MIBs before the default and -M paths, in the order given")
+	flags.BoolVar(&finalNewline, "final-newline", true, "End the output with a newline when writing to stdout")
+	flags.BoolVar(&unexportedVars, "unexported", false, "Only export the module vars, keeping shared type vars unexported like node vars")
+	flags.BoolVar(&emitTablesMap, "tables-map", false, "Emit a Tables map from table entry OIDs to their columns and index")
+	flags.BoolVar(&qualifyDuplicates, "qualify-duplicates", false, "Prefix node vars defined by several modules with their module name instead of failing")
+	flags.StringVar(&oidType, "oid-type", "models", "Element type of an additional per-node OID slice (int, int64, uint, uint32 or uint64), gosnmp for uint32 and a dotted string as used by gosnmp, models emits none")
+	flags.BoolVar(&emitEnumLabels, "enum-labels", false, "Emit the labels of each enumeration as a slice sorted alphabetically")
+	flags.BoolVar(&emitAssertions, "assert-models", false, "Emit compile-time checks that the models package matches the generated code")
+	flags.BoolVar(&emitCellOid, "cell-oid", false, "Give tables in the module var a CellOid method building the OID of a cell from its index values")
+	flags.BoolVar(&emitRegister, "register", false, "Emit an init function per module registering it in the package Registry")
+	flags.BoolVar(&emitNotifyDecoder, "notification-decoder", false, "Emit DecodeNotification to map received varbinds to the objects of a notification")
+	flags.BoolVar(&emitNodeIDs, "node-ids", false, "Emit an ID per node derived from its module and OID for use as an external key")
+	flags.BoolVar(&ownOnly, "own-only", false, "Only generate the nodes defined by the given modules themselves, with stubs for nodes they reference from other modules")
+	flags.BoolVar(&verifyOids, "verify-oids", false, "Check that the formatted OID of every node parses back into its OID before writing anything")
+	flags.BoolVar(&standalone, "standalone", false, "Emit self-contained declarations instead of depending on the gosmi models and types packages")
+	flags.BoolVar(&emitOidArrays, "oid-arrays", false, "Emit an additional fixed-size OID array per node, of the --oid-type elements or uint32, which avoids heap allocations but has a distinct type per OID length")
+	flags.BoolVar(&noDescriptions, "no-descriptions", false, "Leave out the description comments of modules and nodes for smaller files")
+	flags.StringVar(&fromFile, "from-file", "", "File listing further modules to generate, one per line")
+	flags.BoolVar(&smiTypes, "smi-types", false, "Declare Go types for the SMI types Counter32, Counter64, Gauge32 and TimeTicks, alias them as the Value type of every scalar and column of those types and use them for the fields of --native-types, with a Duration method for TimeTicks if any node is of that type")
+	flags.StringArrayVar(&onlyNodes, "only", nil, "Only generate the nodes whose names match the glob, or the regular expression enclosed in slashes, and the nodes they reference")
+	flags.StringArrayVar(&excludeNodes, "exclude", nil, "Don't generate the nodes whose names match the glob, or the regular expression enclosed in slashes, unless another node references them")
+	flags.BoolVar(&oidComments, "oid-comments", false, "Put the dotted OID of each node in a comment next to its var")
+	flags.StringVar(&renameFile, "rename-file", "", "File of mibName=GoName lines renaming nodes and modules in the generated code")
+	flags.BoolVar(&preserveAcronyms, "preserve-acronyms", false, "Keep the parts of module names given with --acronyms upper-case in generated names")
+	flags.StringSliceVar(&acronyms, "acronyms", []string{"IP", "TCP", "HTTP", "ID", "OID"}, "Acronyms kept upper-case by --preserve-acronyms")
+	flags.BoolVar(&flattenTables, "flatten-tables", false, "Give tables in the module var a field per column, next to the embedded table node")
+	flags.BoolVar(&enumParse, "enum-parse", false, "Emit a type with a Parse function returning the value of a label for each enumeration")
+	flags.BoolVar(&enumParseCI, "enum-parse-ci", false, "Match labels case-insensitively in the Parse functions of --enum-parse if they don't match exactly")
+	flags.BoolVar(&enumStrings, "enum-strings", false, "Emit a type with a String method returning the label of a value for each enumeration")
+	flags.BoolVar(&stdoutSplit, "stdout-split", false, "Put a // --- MODULE: NAME --- comment in front of every module generated into the single output of -o")
+	flags.BoolVar(&rangeValidators, "range-validators", false, "Generate a function validating values against the ranges of every type with ranges, or their length for octet strings")
+	flags.BoolVar(&unitWrappers, "unit-wrappers", false, "Generate a type for the values of every scalar and column with UNITS, rendering them with their units")
+	flags.BoolVar(&trapDecoders, "trap-decoders", false, "Generate a decoder of the gosnmp varbinds of every notification, mapping them to the objects it declares")
+	flags.BoolVar(&emitModulesMap, "modules-map", false, "Generate a Modules map of the module structs by module name in modules.go")
+	flags.BoolVar(&keepGoing, "keep-going", false, "Generate the other modules when some fail, reporting all failures at the end")
+	flags.BoolVar(&dryRun, "dry-run", false, "Only log the files that would be created or overwritten and their size, without writing anything")
+	flags.IntVar(&jobs, "jobs", 1, "Number of module files to format and write at the same time")
+	flags.BoolVar(&withImports, "with-imports", false, "Also generate the modules imported by the modules given, apart from the SMI modules themselves")
+	flags.StringVar(&loadDir, "load-dir", "", "Directory to generate all modules found in files below of")
+	flags.IntVar(&commentWidth, "comment-width", 0, "Column at which to word-wrap description comments, 0 to disable")
+	flags.BoolVar(&descriptionsFile, "descriptions-file", false, "Move descriptions from comments into a Descriptions map keyed by OID in descriptions.go")
+	flags.BoolVar(&emitIndexStructs, "index-structs", false, "Emit a comparable struct per table with a typed field per index column, for use as a map key, and its encoding into and decoding from OIDs")
+	flags.BoolVar(&verifyRoundTrips, "round-trip", false, "Parse the generated code and compare the name, OID, type and, with --node-info, access of every node var to the MIB before writing")
+	flags.BoolVar(&lazyNodes, "lazy", false, "Emit node accessors initializing each node on first use through sync.Once instead of node vars")
+	flags.BoolVar(&canonical, "canonical", false, "Generate modules by name and normalize descriptions for output suited to reviewing changes")
+	flags.BoolVar(&nativeTypes, "native-types", false, "Emit structs holding the values of the scalars of each module and the columns of each row as native Go types")
+	flags.BoolVar(&emitEnumConsts, "enum-consts", false, "Emit a type with a constant per value for each enumeration, and a constant per bit and a HasBit helper for each BITS type")
+	flags.BoolVar(&emitOidIndex, "oid-index", false, "Emit an OidIndex map from the OIDs of all nodes to the nodes in index.go")
+	flags.BoolVar(&emitResolve, "resolve", false, "Emit Resolve to look up the scalar or column an OID belongs to by longest prefix")
+	flags.StringVar(&outputFormat, "format", "go", "Output format, go for Go source or json for the nodes of each module as a JSON array sorted by OID")
+	flags.BoolVar(&skipObsolete, "skip-obsolete", false, "Leave out obsolete nodes and references to them")
+	flags.BoolVar(&skipDeprecated, "skip-deprecated", false, "Leave out deprecated nodes and references to them")
+	flags.BoolVar(&displayHints, "display-hint", false, "Emit a FormatValue helper rendering raw values according to the DISPLAY-HINT of each octet string type")
+	flags.BoolVar(&subpackages, "subpackages", false, "Generate each module into a subpackage of -d and the shared types into the mibtypes subpackage")
+	flags.StringVar(&importPath, "import-path", "", "Import path of -d, which --subpackages imports the subpackages by")
+	flags.StringVar(&typeConflicts, "on-type-conflict", "error", "How to handle types of different modules with the same name but a different definition: error, rename to prefix them with their module, or first to use the first one")
+	flags.BoolVar(&resolveImports, "resolve-imports", true, "Generate shared types defined by imported modules that aren't generated, instead of failing")
+	flags.StringVar(&buildTag, "build-tag", "", "Build constraint to put at the top of every generated file, e.g. mibs or linux && !nomibs")
+	flags.BoolVar(&conformance, "conformance", false, "Generate MODULE-COMPLIANCE statements with their mandatory and optional groups and object refinements")
+	flags.StringVar(&headerFile, "header-file", "", "File with the header to put above the package clause of every generated file instead of the built-in one, a template with the package name as {{.Package}}")
+	flags.BoolVar(&emitTableRanges, "table-ranges", false, "Emit the range of OIDs covered by each table to bound walks")
+	flags.BoolVar(&emitSyntax, "syntax", false, "Emit the SMI syntax of scalars and columns as a readable string")
+	flags.IntVar(&formatChunkSize, "format-chunk-size", 8<<20, "Size in bytes above which a generated file is formatted in chunks, 0 to disable")
+	flags.BoolVar(&emitLanguage, "language", false, "Emit a constant per module with the SMI version it is written in")
+	flags.BoolVar(&emitNodeInfo, "node-info", false, "Emit the STATUS of each node and the MAX-ACCESS of scalars and columns into their NodeInfo")
+	flags.BoolVar(&emitModuleOid, "module-oid", false, "Emit a var per module with the OID it is rooted at")
+	flags.BoolVar(&emitModuleInfo, "module-info", false, "Emit a var per module with its ORGANIZATION, CONTACT-INFO and revisions")
+	flags.BoolVar(&emitNodeLookup, "node-lookup", false, "Emit a Node method on each module struct looking up its nodes by their name in the MIB")
+	flags.BoolVar(&emitKindAccessors, "kind-accessors", false, "Emit Scalars and Tables methods on each module struct returning its scalars and tables")
+	flags.BoolVar(&emitEnterprise, "enterprise", false, "Emit a constant per module rooted under enterprises with its private enterprise number")
 }
@@ -0,0 +1,2171 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// update makes golden tests write the golden files instead of comparing
+// against them, for changes to the generated code.
+var update = flag.Bool("update", false, "Update the golden files in testdata")
+
+// generateFixture generates the modules of the fixture in testdata/fixture
+// with the given flags, builds the generated package and returns its source.
+// The package is generated into testdata, so it resolves its imports the same
+// way this package does, and the build catches any generated code that
+// doesn't compile against gosmi.
+func generateFixture(t *testing.T, fixture string, flags ...string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir(filepath.Join("..", "testdata"), "generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = generateSources(fixtureSources(t, fixture), append([]string{"--dir", dir, "--package", "generated"}, flags...))
+	if err != nil {
+		t.Fatalf("Generating %s: %v", fixture, err)
+	}
+
+	output, err := exec.Command("go", "build", "./"+filepath.ToSlash(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("Building the code generated for %s: %v\n%s", fixture, err, output)
+	}
+
+	return readGenerated(t, dir)
+}
+
+// runFixture generates the modules of the fixture in testdata/fixture with
+// the given flags into a main package along with main, which declares the
+// main function, and returns the trimmed output of running it.
+func runFixture(t *testing.T, fixture string, main string, flags ...string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir(filepath.Join("..", "testdata"), "generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = generateSources(fixtureSources(t, fixture), append([]string{"--dir", dir, "--package", "main"}, flags...))
+	if err != nil {
+		t.Fatalf("Generating %s: %v", fixture, err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nimport \"fmt\"\n\n"+main), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command("go", "run", "./"+filepath.ToSlash(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("Running the code generated for %s: %v\n%s", fixture, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// fixtureSources returns the sources of the modules of the fixture in
+// testdata/fixture keyed by their filename.
+func fixtureSources(t *testing.T, fixture string) map[string]string {
+	t.Helper()
+
+	fixtureDir := filepath.Join("..", "testdata", fixture)
+	files, err := ioutil.ReadDir(fixtureDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := make(map[string]string)
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		source, err := ioutil.ReadFile(filepath.Join(fixtureDir, file.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sources[file.Name()] = string(source)
+	}
+	return sources
+}
+
+// runGenerated runs a main package made of body, declarations as generated
+// with the given imports, and main, which declares the main function, and
+// returns its trimmed output.
+func runGenerated(t *testing.T, imports imports, body, main string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir(filepath.Join("..", "testdata"), "generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	imports.add("fmt")
+	err = writeGeneratedFile(filepath.Join(dir, "main.go"), nil, "main", imports, []byte(body+main))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command("go", "run", "./"+filepath.ToSlash(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("Running the generated code: %v\n%s", err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// readGenerated returns the Go files in dir concatenated in order of their
+// names.
+func readGenerated(t *testing.T, dir string) string {
+	t.Helper()
+
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(filenames)
+
+	var generated strings.Builder
+	for _, filename := range filenames {
+		b, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		generated.Write(b)
+	}
+	return generated.String()
+}
+
+// assertContains fails t for each of want that generated doesn't contain.
+func assertContains(t *testing.T, generated string, want ...string) {
+	t.Helper()
+
+	for _, s := range want {
+		if !strings.Contains(generated, s) {
+			t.Errorf("Generated code doesn't contain %q", s)
+		}
+	}
+}
+
+// assertNotContains fails t for each of unwanted that generated contains.
+func assertNotContains(t *testing.T, generated string, unwanted ...string) {
+	t.Helper()
+
+	for _, s := range unwanted {
+		if strings.Contains(generated, s) {
+			t.Errorf("Generated code contains %q", s)
+		}
+	}
+}
+
+func TestStatus(t *testing.T) {
+	generated := generateFixture(t, "status", "--node-info")
+	assertContains(t, generated,
+		"var fixtureStatusCurrentNodeInfo = NodeInfo{\n\tStatus: types.StatusCurrent,\n",
+		"var fixtureStatusDeprecatedNodeInfo = NodeInfo{\n\tStatus: types.StatusDeprecated,\n",
+		"var fixtureStatusObsoleteNodeInfo = NodeInfo{\n\tStatus: types.StatusObsolete,\n",
+		"var fixtureStatusObsoleteColumnNodeInfo = NodeInfo{\n\tStatus: types.StatusObsolete,\n",
+	)
+
+	generated = generateFixture(t, "status")
+	assertNotContains(t, generated, "Status: types.Status")
+}
+
+func TestSkipObsolete(t *testing.T) {
+	generated := generateFixture(t, "status", "--skip-obsolete")
+	assertContains(t, generated, "fixtureStatusCurrentNode", "fixtureStatusDeprecatedNode", "fixtureStatusDeprecatedColumnNode")
+	assertNotContains(t, generated, "fixtureStatusObsoleteNode", "fixtureStatusObsoleteColumnNode")
+}
+
+func TestSkipDeprecated(t *testing.T) {
+	generated := generateFixture(t, "status", "--skip-deprecated")
+	assertContains(t, generated, "fixtureStatusCurrentNode", "fixtureStatusObsoleteNode", "fixtureStatusObsoleteColumnNode")
+	assertNotContains(t, generated, "fixtureStatusDeprecatedNode", "fixtureStatusDeprecatedColumnNode")
+}
+
+func TestAccess(t *testing.T) {
+	generated := generateFixture(t, "access", "--round-trip", "--node-info")
+	assertContains(t, generated,
+		"var fixtureAccessReadOnlyNodeInfo = NodeInfo{\n\tStatus: types.StatusCurrent,\n\tAccess: types.AccessReadOnly,\n}",
+		"var fixtureAccessReadWriteNodeInfo = NodeInfo{\n\tStatus: types.StatusCurrent,\n\tAccess: types.AccessReadWrite,\n}",
+		"var fixtureAccessNotifyNodeInfo = NodeInfo{\n\tStatus: types.StatusCurrent,\n\tAccess: types.AccessNotify,\n}",
+		"var fixtureAccessIndexNodeInfo = NodeInfo{\n\tStatus: types.StatusCurrent,\n\tAccess: types.AccessNotAccessible,\n}",
+		"var fixtureAccessValueNodeInfo = NodeInfo{\n\tStatus: types.StatusCurrent,\n\tAccess: types.AccessReadOnly,\n}",
+		"var fixtureAccessRowStatusNodeInfo = NodeInfo{\n\tStatus: types.StatusCurrent,\n\tAccess: types.AccessReadWrite,\n}",
+	)
+	assertNotContains(t, generated, "var fixtureAccessTableNodeInfo = NodeInfo{\n\tStatus: types.StatusCurrent,\n\tAccess:")
+
+	generated = generateFixture(t, "access", "--round-trip")
+	assertNotContains(t, generated, "Access: types.Access")
+}
+
+func TestSyntax(t *testing.T) {
+	generated := generateFixture(t, "syntax", "--syntax")
+	assertContains(t, generated,
+		`Syntax: "INTEGER (0..65535)",`,
+		`Syntax: "INTEGER (-10..-1 | 1..10)",`,
+		`Syntax: "OCTET STRING (SIZE(0..255))",`,
+		`Syntax: "OCTET STRING (SIZE(4 | 16))",`,
+		`Syntax: "INTEGER { up(1), down(2), testing(3) }",`,
+		`Syntax: "DisplayString (SIZE(0..255))",`,
+	)
+}
+
+func TestTrapType(t *testing.T) {
+	generated := generateFixture(t, "trap-type")
+	assertContains(t, generated,
+		`OidFormatted: "1.3.6.1.4.1.99999.43.0.7",`,
+		"\tEnterprise:   models.Oid{0x1, 0x3, 0x6, 0x1, 0x4, 0x1, 0x1869f, 0x2b},\n\tSpecificTrap: 7,\n",
+	)
+}
+
+func TestNotifyOnly(t *testing.T) {
+	generated := generateFixture(t, "access")
+	assertContains(t, generated,
+		"var fixtureAccessNotificationNodeInfo = NodeInfo{\n\tNotifyOnly: []models.ScalarNode{\n\t\tfixtureAccessNotifyNode,\n\t},\n}",
+	)
+}
+
+func TestNotifyScalarOid(t *testing.T) {
+	generated := generateFixture(t, "access")
+	assertContains(t, generated,
+		"\t\tName:         \"fixtureAccessReadOnly\",\n\t\tOid:          models.Oid{0x1, 0x3, 0x6, 0x1, 0x4, 0x1, 0x1869f, 0x29, 0x1, 0x0},\n\t\tOidFormatted: \"1.3.6.1.4.1.99999.41.1.0\",\n\t\tOidLen:       10,\n",
+		"\t\tName:         \"fixtureAccessNotify\",\n\t\tOid:          models.Oid{0x1, 0x3, 0x6, 0x1, 0x4, 0x1, 0x1869f, 0x29, 0x3},\n\t\tOidFormatted: \"1.3.6.1.4.1.99999.41.3\",\n\t\tOidLen:       9,\n",
+	)
+}
+
+func TestNodeIDs(t *testing.T) {
+	generated := generateFixture(t, "status", "--node-ids")
+	// The SHA-256 of "FIXTURE-STATUS-MIB::1.3.6.1.4.1.99999.40.1", which mustn't
+	// change between runs or platforms.
+	assertContains(t, generated, "var fixtureStatusCurrentNodeInfo = NodeInfo{\n\tID: \"142df65b7f1f9b3a\",\n}")
+	if regenerated := generateFixture(t, "status", "--node-ids"); regenerated != generated {
+		t.Error("Regenerating the fixture changed the generated code")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	generated := generateFixture(t, "defval", "--node-info")
+	assertContains(t, generated,
+		"\tDefault: int64(300),\n",
+		"\tDefault: int64(2),\n",
+		"\tDefault: []byte(\"none\"),\n",
+		"var fixtureDefvalRowStatusNodeInfo = NodeInfo{\n\tStatus: types.StatusCurrent,\n\tAccess: types.AccessReadWrite,\n}",
+	)
+}
+
+func TestAugments(t *testing.T) {
+	generated := generateFixture(t, "augments")
+	assertContains(t, generated,
+		"\tIndex: []models.ColumnNode{\n\t\tfixtureBaseIndexNode,\n\t},\n}\nvar fixtureAugEntryNodeInfo = NodeInfo{\n\tAugments: fixtureBaseEntryNode.BaseNode,\n}",
+	)
+}
+
+func TestModuleInfo(t *testing.T) {
+	generated := generateFixture(t, "module-info", "--module-info")
+	assertContains(t, generated, `var FixtureInfoMibModuleInfo = ModuleInfo{
+	LastUpdated:  time.Date(2018, time.February, 15, 9, 30, 0, 0, time.UTC),
+	Organization: "mib2go",
+	ContactInfo:  "https://github.com/sleepinggenius2/mib2go",
+	Revisions: []models.Revision{
+		{Date: time.Date(2018, time.February, 15, 9, 30, 0, 0, time.UTC), Description: "Added fixtureInfoCount."},
+		{Date: time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC), Description: "Initial revision."},
+	},
+}`)
+
+	generated = generateFixture(t, "module-info")
+	assertNotContains(t, generated, "ModuleInfo", `"time"`)
+}
+
+func TestModuleInfoLastUpdated(t *testing.T) {
+	generated := generateFixture(t, "module-info", "--module-info")
+	assertContains(t, generated, "\tLastUpdated  time.Time\n", "\tLastUpdated:  time.Date(2018, time.February, 15, 9, 30, 0, 0, time.UTC),\n")
+
+	// Without revisions, nothing needs the time package.
+	generated = generateFixture(t, "trap-type", "--module-info")
+	assertContains(t, generated, "var FixtureTrapMibModuleInfo = ModuleInfo{")
+	assertNotContains(t, generated, "LastUpdated", `"time"`)
+}
+
+func TestLanguage(t *testing.T) {
+	generated := generateFixture(t, "trap-type", "--language")
+	assertContains(t, generated, "const FixtureTrapMibLanguage = types.LanguageSMIv1\n")
+
+	generated = generateFixture(t, "status", "--language")
+	assertContains(t, generated, "const FixtureStatusMibLanguage = types.LanguageSMIv2\n")
+
+	generated = generateFixture(t, "status")
+	assertNotContains(t, generated, "Language")
+}
+
+func TestNextSiblingOid(t *testing.T) {
+	for _, test := range []struct {
+		oid  models.Oid
+		want models.Oid
+	}{
+		{models.Oid{1, 3, 6, 1, 2, 1, 2, 2, 1}, models.Oid{1, 3, 6, 1, 2, 1, 2, 2, 2}},
+		{models.Oid{1, 3, 6, 1, 4, 1, math.MaxUint32}, models.Oid{1, 3, 6, 1, 4, 2}},
+		{models.Oid{math.MaxUint32, math.MaxUint32}, nil},
+		{models.Oid{}, nil},
+	} {
+		if got := nextSiblingOid(test.oid); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Next sibling of %v is %v, want %v", test.oid, got, test.want)
+		}
+	}
+}
+
+func TestTableRanges(t *testing.T) {
+	output := runFixture(t, "access", `func main() {
+	for _, oid := range [][]uint32{
+		{1, 3, 6, 1, 4, 1, 99999, 41, 4},
+		{1, 3, 6, 1, 4, 1, 99999, 41, 4, 1},
+		{1, 3, 6, 1, 4, 1, 99999, 41, 4, 1, 2, 7},
+		{1, 3, 6, 1, 4, 1, 99999, 41, 4, 2},
+		{1, 3, 6, 1, 4, 1, 99999, 41, 5},
+	} {
+		fmt.Println(fixtureAccessTableNodeRange.Contains(oid))
+	}
+}`, "--table-ranges")
+	want := "false\ntrue\ntrue\nfalse\nfalse"
+	if output != want {
+		t.Errorf("Unexpected table range:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestTablesMap(t *testing.T) {
+	generated := generateFixture(t, "tables", "--tables-map")
+	assertContains(t, generated, `var Tables = map[string]TableInfo{
+	"1.3.6.1.4.1.99999.60.1.1": {Table: fixtureHostTableNode, Columns: fixtureHostEntryNode.Columns, Index: fixtureHostEntryNode.Index},
+	"1.3.6.1.4.1.99999.60.2.1": {Table: fixturePortTableNode, Columns: fixturePortEntryNode.Columns, Index: fixturePortEntryNode.Index},
+}`)
+
+	output := runFixture(t, "tables", `func main() {
+	fmt.Println(len(Tables))
+	for _, oid := range []string{"1.3.6.1.4.1.99999.60.1.1", "1.3.6.1.4.1.99999.60.2.1"} {
+		table := Tables[oid]
+		fmt.Println(table.Table.Name, len(table.Columns), len(table.Index), table.Index[len(table.Index)-1].Name)
+	}
+}`, "--tables-map")
+	want := "2\nfixtureHostTable 2 1 fixtureHostIndex\nfixturePortTable 3 2 fixturePortName"
+	if output != want {
+		t.Errorf("Unexpected tables:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestIndexStructs(t *testing.T) {
+	generated := generateFixture(t, "tables", "--index-structs")
+	assertContains(t, generated,
+		"type FixturePortTableIndex struct {\n\tFixturePortHost int32\n\tFixturePortName string\n}\n",
+		"func (index FixturePortTableIndex) Encode() (models.Oid, error) {\n",
+		"func DecodeFixturePortTableIndex(suffix models.Oid) (FixturePortTableIndex, error) {\n",
+	)
+
+	// Strings keep the struct comparable, so it can be a map key.
+	output := runFixture(t, "tables", `func main() {
+	index := FixturePortTableIndex{FixturePortHost: 3, FixturePortName: "eth0"}
+	oid, err := index.Encode()
+	fmt.Println(oid, err)
+	decoded, err := DecodeFixturePortTableIndex(oid)
+	rows := map[FixturePortTableIndex]bool{index: true}
+	fmt.Println(decoded, rows[decoded], err)
+	_, err = DecodeFixturePortTableIndex(oid[:len(oid)-1])
+	fmt.Println(err)
+	_, err = DecodeFixturePortTableIndex(append(oid, 1))
+	fmt.Println(err)
+}`, "--index-structs")
+	want := "3.4.101.116.104.48 <nil>\n" +
+		"{3 eth0} true <nil>\n" +
+		"Index value needs 4 sub-identifiers, only 3 left\n" +
+		"Index has 1 sub-identifiers too many"
+	if output != want {
+		t.Errorf("Unexpected round trip:\n%s\nwant:\n%s", output, want)
+	}
+
+	// The IMPLIED name takes up the rest of the OID without its length.
+	output = runFixture(t, "implied", `func main() {
+	index := FixtureImpliedTableIndex{FixtureImpliedGroup: 2, FixtureImpliedName: "ab"}
+	oid, err := index.Encode()
+	fmt.Println(oid, err)
+	fmt.Println(DecodeFixtureImpliedTableIndex(oid))
+}`, "--index-structs")
+	want = "2.97.98 <nil>\n{2 ab} <nil>"
+	if output != want {
+		t.Errorf("Unexpected round trip of an IMPLIED index:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestAssertModels(t *testing.T) {
+	// The status fixture has nodes of every kind with a model, and
+	// generateFixture builds the checks of each of them.
+	generated := generateFixture(t, "status", "--assert-models")
+	assertContains(t, generated,
+		"_ string     = models.BaseNode{}.OidFormatted\n",
+		"_ models.Type     = models.ScalarNode{}.Type\n",
+		"_ models.RowNode  = models.TableNode{}.Row\n",
+		"_ bool                = models.RowNode{}.Implied\n",
+		"_ models.ScalarNode = models.ColumnNode{}.ScalarNode\n",
+		"_ []models.ScalarNode = models.NotificationNode{}.Objects\n",
+	)
+
+	generated = generateFixture(t, "tables", "--assert-models")
+	assertContains(t, generated, "models.TableNode{}.Row", "models.ColumnNode{}.ScalarNode")
+	assertNotContains(t, generated, "models.ScalarNode{}.Type", "models.NotificationNode{}")
+}
+
+func TestCellOid(t *testing.T) {
+	generated := generateFixture(t, "tables", "--cell-oid")
+	assertContains(t, generated,
+		"\tFixturePortTable FixturePortTable\n",
+		"func (t FixturePortTable) CellOid(column models.ColumnNode, index ...interface{}) (models.Oid, error) {\n",
+	)
+	assertNotContains(t, generated, "func CellOid(")
+
+	output := runFixture(t, "tables", `func main() {
+	fmt.Println(FixtureTablesMib.FixturePortTable.CellOid(fixturePortSpeedNode, 3, "eth0"))
+	fmt.Println(FixtureTablesMib.FixtureHostTable.CellOid(fixtureHostNameNode, 7))
+	_, err := FixtureTablesMib.FixturePortTable.CellOid(fixturePortSpeedNode, 3)
+	fmt.Println(err)
+}`, "--cell-oid")
+	want := "1.3.6.1.4.1.99999.60.2.1.3.3.4.101.116.104.48 <nil>\n" +
+		"1.3.6.1.4.1.99999.60.1.1.2.7 <nil>\n" +
+		"Got 1 index values for 2 index columns"
+	if output != want {
+		t.Errorf("Unexpected cell OIDs:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestOidArrays(t *testing.T) {
+	generated := generateFixture(t, "access", "--oid-arrays")
+	assertContains(t, generated,
+		"var fixtureAccessReadOnlyNodeOidArray = [10]uint32{1, 3, 6, 1, 4, 1, 99999, 41, 1, 0}\n",
+		"var fixtureAccessValueNodeOidArray = [11]uint32{1, 3, 6, 1, 4, 1, 99999, 41, 4, 1, 2}\n",
+	)
+
+	generated = generateFixture(t, "access", "--oid-arrays", "--oid-type", "uint")
+	assertContains(t, generated, "var fixtureAccessValueNodeOidArray = [11]uint{1, 3, 6, 1, 4, 1, 99999, 41, 4, 1, 2}\n")
+}
+
+// The OID of fixtureAccessValue as --oid-arrays emits it and as a slice, which
+// BenchmarkOidArrays builds the OID of a cell from.
+var (
+	benchOidSlice = models.Oid{1, 3, 6, 1, 4, 1, 99999, 41, 4, 1, 2}
+	benchOidArray = [11]uint32{1, 3, 6, 1, 4, 1, 99999, 41, 4, 1, 2}
+
+	benchCellSlice models.Oid
+	benchCellArray [12]uint32
+)
+
+func BenchmarkOidArrays(b *testing.B) {
+	b.Run("slice", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cell := make(models.Oid, len(benchOidSlice), len(benchOidSlice)+1)
+			copy(cell, benchOidSlice)
+			benchCellSlice = append(cell, uint32(i))
+		}
+	})
+	b.Run("array", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var cell [12]uint32
+			copy(cell[:], benchOidArray[:])
+			cell[len(benchOidArray)] = uint32(i)
+			benchCellArray = cell
+		}
+	})
+}
+
+func TestLazy(t *testing.T) {
+	main := `func main() {
+	node, ok := FixtureStatusMib.Node("fixtureStatusIndex")
+	fmt.Println(node.Name, node.OidFormatted, ok)
+	fmt.Println(len(FixtureStatusMib.Scalars()), len(FixtureStatusMib.Tables()))
+}`
+	want := runFixture(t, "status", main, "--node-lookup", "--kind-accessors")
+	if got := runFixture(t, "status", main, "--node-lookup", "--kind-accessors", "--lazy"); got != want {
+		t.Errorf("Lazy nodes differ:\n%s\nwant:\n%s", got, want)
+	}
+
+	generated := generateFixture(t, "status", "--lazy")
+	assertContains(t, generated, "\tFixtureStatusCurrent          func() models.ScalarNode\n")
+	assertNotContains(t, generated, "var fixtureStatusCurrentNode = models.ScalarNode{")
+}
+
+// BenchmarkLazyStartup runs a program using one node of 40 modules of 50
+// scalars each, generated with and without --lazy, which only differ in how
+// long it takes to initialize the package.
+func BenchmarkLazyStartup(b *testing.B) {
+	sources := jobsSources(40)
+	for _, lazy := range []bool{false, true} {
+		b.Run(fmt.Sprintf("lazy=%t", lazy), func(b *testing.B) {
+			dir, err := ioutil.TempDir(filepath.Join("..", "testdata"), "generated")
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			err = generateSources(sources, []string{"--dir", dir, "--package", "main", "--node-lookup", fmt.Sprintf("--lazy=%t", lazy)})
+			if err != nil {
+				b.Fatal(err)
+			}
+			main := "package main\n\nfunc main() {\n\tJobsTest0Mib.Node(\"jobsTest0Scalar1\")\n}\n"
+			err = ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(main), 0644)
+			if err != nil {
+				b.Fatal(err)
+			}
+			binary := filepath.Join(dir, "startup")
+			output, err := exec.Command("go", "build", "-o", binary, "./"+filepath.ToSlash(dir)).CombinedOutput()
+			if err != nil {
+				b.Fatalf("Building the generated code: %v\n%s", err, output)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := exec.Command(binary).Run(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestNativeTypes(t *testing.T) {
+	for baseType, want := range map[types.BaseType]string{
+		types.BaseTypeInteger32:        "int32",
+		types.BaseTypeOctetString:      "[]byte",
+		types.BaseTypeObjectIdentifier: "[]uint32",
+		types.BaseTypeUnsigned32:       "uint32",
+		types.BaseTypeInteger64:        "int64",
+		types.BaseTypeUnsigned64:       "uint64",
+		types.BaseTypeFloat32:          "float32",
+		types.BaseTypeFloat64:          "float64",
+		types.BaseTypeFloat128:         "",
+		types.BaseTypeEnum:             "int32",
+		types.BaseTypeBits:             "[]byte",
+		types.BaseTypePointer:          "",
+		types.BaseTypeUnknown:          "",
+	} {
+		if got := nativeFieldTypes[baseType]; got != want {
+			t.Errorf("Native type of %s is %q, want %q", baseType, got, want)
+		}
+	}
+
+	generated := generateFixture(t, "base-types", "--native-types")
+	assertContains(t, generated, `type FixtureBaseTypesMibValues struct {
+	FixtureBaseInteger   int32
+	FixtureBaseString    []byte
+	FixtureBaseOid       []uint32
+	FixtureBaseUnsigned  uint32
+	FixtureBaseCounter64 uint64
+	FixtureBaseEnum      int32
+	FixtureBaseBits      []byte
+}`)
+
+	output := runFixture(t, "base-types", `func main() {
+	fmt.Printf("%+v\n", FixtureBaseTypesMibValues{
+		FixtureBaseInteger:   -1,
+		FixtureBaseString:    []byte("a"),
+		FixtureBaseOid:       []uint32{1, 3, 6},
+		FixtureBaseUnsigned:  1<<32 - 1,
+		FixtureBaseCounter64: 1<<64 - 1,
+		FixtureBaseEnum:      2,
+		FixtureBaseBits:      []byte{0x80},
+	})
+}`, "--native-types")
+	want := "{FixtureBaseInteger:-1 FixtureBaseString:[97] FixtureBaseOid:[1 3 6] FixtureBaseUnsigned:4294967295 FixtureBaseCounter64:18446744073709551615 FixtureBaseEnum:2 FixtureBaseBits:[128]}"
+	if output != want {
+		t.Errorf("Unexpected values:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestBits(t *testing.T) {
+	// Bits 0, 7, 8 and 9 are set: bit 0 is the most significant bit of the
+	// first octet and bits 8 and 9 are the two most significant of the second.
+	output := runFixture(t, "base-types", `func main() {
+	value := []byte{0x81, 0xc0}
+	for bit := FixtureBaseBitsBitB0; bit <= FixtureBaseBitsBitB9+1; bit++ {
+		fmt.Print(FixtureBaseBitsHasBit(value, bit), " ")
+	}
+}`, "--enum-consts")
+	want := "true false false false false false false true true true false"
+	if output != want {
+		t.Errorf("Decoded bits are %s, want %s", output, want)
+	}
+}
+
+func TestEnumStrings(t *testing.T) {
+	output := runFixture(t, "base-types", `func main() {
+	fmt.Println(FixtureBaseEnum(1), FixtureBaseEnum(2), FixtureBaseEnum(7))
+}`, "--enum-strings")
+	if output != "up down unknown(7)" {
+		t.Errorf("Expected the labels and unknown(7), got %s", output)
+	}
+}
+
+func TestEnumParse(t *testing.T) {
+	err := generateSources(fixtureSources(t, "base-types"), []string{"--enum-parse-ci"})
+	if err == nil {
+		t.Error("Expected --enum-parse-ci without --enum-parse to be rejected")
+	}
+
+	main := `func main() {
+	for _, s := range []string{"down", "DOWN", "sideways"} {
+		e, ok := ParseFixtureBaseEnum(s)
+		fmt.Print(int64(e), " ", ok, " ")
+	}
+}`
+	output := runFixture(t, "base-types", main, "--enum-parse")
+	if want := "2 true 0 false 0 false"; output != want {
+		t.Errorf("Parsed labels exactly as %s, want %s", output, want)
+	}
+	output = runFixture(t, "base-types", main, "--enum-parse", "--enum-parse-ci")
+	if want := "2 true 2 true 0 false"; output != want {
+		t.Errorf("Parsed labels case-insensitively as %s, want %s", output, want)
+	}
+}
+
+func TestModuleOid(t *testing.T) {
+	generated := generateFixture(t, "status", "--module-oid")
+	assertContains(t, generated,
+		"// FixtureStatusMibModuleOid is the OID of the MODULE-IDENTITY of FIXTURE-STATUS-MIB.\nvar FixtureStatusMibModuleOid = models.Oid{1, 3, 6, 1, 4, 1, 99999, 40}\n",
+		"const FixtureStatusMibModuleOidFormatted = \"1.3.6.1.4.1.99999.40\"\n",
+	)
+
+	generated = generateFixture(t, "trap-type", "--module-oid")
+	assertContains(t, generated,
+		"// which has no MODULE-IDENTITY.\nvar FixtureTrapMibModuleOid = models.Oid{1, 3, 6, 1, 4, 1, 99999, 43}\n",
+		"const FixtureTrapMibModuleOidFormatted = \"1.3.6.1.4.1.99999.43\"\n",
+	)
+
+	generated = generateFixture(t, "status")
+	assertNotContains(t, generated, "ModuleOid")
+}
+
+func TestEnterprise(t *testing.T) {
+	generated := generateFixture(t, "status", "--enterprise")
+	assertContains(t, generated, "const FixtureStatusMibEnterprise uint32 = 99999\n")
+	output := runFixture(t, "status", "func main() {\n\tfmt.Println(FixtureStatusMibEnterprise)\n}\n", "--enterprise")
+	if output != "99999" {
+		t.Errorf("Enterprise is %s, want 99999", output)
+	}
+
+	generated = generateFixture(t, "experimental", "--enterprise")
+	assertNotContains(t, generated, "Enterprise")
+
+	generated = generateFixture(t, "status")
+	assertNotContains(t, generated, "FixtureStatusMibEnterprise")
+}
+
+func TestModuleRootOid(t *testing.T) {
+	node := func(oid ...uint32) gosmi.SmiNode {
+		var node gosmi.SmiNode
+		node.Oid = oid
+		return node
+	}
+	nodes := []gosmi.SmiNode{node(1, 3, 6, 1, 4, 1, 9, 1, 1), node(1, 3, 6, 1, 4, 1, 9, 2), node(1, 3, 6, 1, 4, 1, 9, 1)}
+	if oid, identity := moduleRootOid(gosmi.SmiModule{}, nodes); identity || !reflect.DeepEqual(oid, models.Oid{1, 3, 6, 1, 4, 1, 9}) {
+		t.Errorf("Root OID is %v (identity %t), want 1.3.6.1.4.1.9", oid, identity)
+	}
+	if oid, _ := moduleRootOid(gosmi.SmiModule{}, nil); len(oid) != 0 {
+		t.Errorf("Root OID of no nodes is %v, want none", oid)
+	}
+}
+
+func TestNoDescriptions(t *testing.T) {
+	generated := generateFixture(t, "status")
+	lean := generateFixture(t, "status", "--no-descriptions")
+	if len(lean) >= len(generated) {
+		t.Errorf("Expected fewer than %d bytes without descriptions, got %d", len(generated), len(lean))
+	}
+	assertContains(t, generated, "Module of the STATUS fixture.")
+	assertNotContains(t, lean, "Module of the STATUS fixture.", "/*\n")
+}
+
+func TestGenerateComment(t *testing.T) {
+	for description, want := range map[string]string{
+		"a */ b":     "/*\na * / b\n*/\n",
+		"a /* b":     "/*\na / * b\n*/\n",
+		"/*/ x */*/": "/*\n/ * / x * / * /\n*/\n",
+		"":           "",
+		" \n\t":      "",
+	} {
+		buf := &bytes.Buffer{}
+		generateComment(buf, description)
+		if buf.String() != want {
+			t.Errorf("Comment of %q is %q, want %q", description, buf, want)
+		}
+		if _, err := format.Source([]byte("package p\n\n" + buf.String() + "var x int\n")); err != nil {
+			t.Errorf("Comment of %q: %v", description, err)
+		}
+	}
+
+	generated := generateFixture(t, "comments")
+	assertContains(t, generated,
+		"/*\nA description opening a / * comment.\n*/\nvar fixtureCommentsOpenNode = ",
+		"/*\nA description closing a * / comment.\n*/\nvar fixtureCommentsCloseNode = ",
+		"}\nvar fixtureCommentsEmptyNode = ",
+	)
+}
+
+func TestWrapComment(t *testing.T) {
+	var words []string
+	for len(strings.Join(words, " ")) < 300 {
+		words = append(words, "walk", "1.3.6.1.4.1.2021.10.1.3")
+	}
+	line := strings.Join(words, " ")
+	long := strings.Repeat("x", 100)
+
+	wrapped := wrapComment("  "+line+"\n\nShort.\n"+long, 80)
+	lines := strings.Split(wrapped, "\n")
+	var joined []string
+	for _, wrappedLine := range lines {
+		if wrappedLine == "" {
+			break
+		}
+		if utf8.RuneCountInString(wrappedLine) > 80 {
+			t.Errorf("Line longer than 80 columns: %q", wrappedLine)
+		}
+		if !strings.HasPrefix(wrappedLine, "  ") {
+			t.Errorf("Line lost its indentation: %q", wrappedLine)
+		}
+		joined = append(joined, strings.TrimSpace(wrappedLine))
+	}
+	if len(joined) < 4 || strings.Join(joined, " ") != line {
+		t.Errorf("Expected the words to be kept, got:\n%s", wrapped)
+	}
+	if !strings.HasSuffix(wrapped, "\n\nShort.\n"+long) {
+		t.Errorf("Expected the newlines and the long word to be kept, got:\n%s", wrapped)
+	}
+}
+
+func TestGoSnmpOids(t *testing.T) {
+	// The OIDs are encoded into a packet by gosnmp and parsed back from it.
+	output := runFixture(t, "status", `import "github.com/gosnmp/gosnmp"
+
+func main() {
+	oids := map[string][]uint32{
+		fixtureStatusCurrentNodeGoSnmpOid: fixtureStatusCurrentNodeOid,
+		fixtureStatusIndexNodeGoSnmpOid:   fixtureStatusIndexNodeOid,
+	}
+	for _, name := range []string{fixtureStatusCurrentNodeGoSnmpOid, fixtureStatusIndexNodeGoSnmpOid} {
+		packet := &gosnmp.SnmpPacket{
+			Version:   gosnmp.Version2c,
+			Community: "public",
+			PDUType:   gosnmp.GetRequest,
+			Variables: []gosnmp.SnmpPDU{{Name: name, Type: gosnmp.Null}},
+		}
+		b, err := packet.MarshalMsg()
+		if err != nil {
+			panic(err)
+		}
+		decoded, err := gosnmp.Default.SnmpDecodePacket(b)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(name, decoded.Variables[0].Name == name, fmt.Sprint(oids[name]))
+	}
+}`, "--oid-type", "gosnmp")
+	want := `.1.3.6.1.4.1.99999.40.1.0 true [1 3 6 1 4 1 99999 40 1 0]
+.1.3.6.1.4.1.99999.40.4.1.1 true [1 3 6 1 4 1 99999 40 4 1 1]`
+	if output != want {
+		t.Errorf("Unexpected OIDs:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestTrapDecoders(t *testing.T) {
+	output := runFixture(t, "access", `import "github.com/gosnmp/gosnmp"
+
+func main() {
+	vbs := []gosnmp.SnmpPDU{
+		{Name: ".1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(4200)},
+		{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.99999.41.5"},
+		{Name: ".1.3.6.1.4.1.99999.41.1.0", Type: gosnmp.Integer, Value: 5},
+		{Name: ".1.3.6.1.4.1.99999.41.3", Type: gosnmp.Integer, Value: 7},
+	}
+	fmt.Println(DecodeFixtureAccessNotificationNodeTrap(vbs))
+	fmt.Println(DecodeFixtureAccessNotificationNodeTrap(vbs[:3]))
+}`, "--trap-decoders")
+	want := `map[fixtureAccessNotify:7 fixtureAccessReadOnly:5] <nil>
+map[fixtureAccessReadOnly:5] Missing objects fixtureAccessNotify`
+	if output != want {
+		t.Errorf("Unexpected decoded varbinds:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestUnitWrappers(t *testing.T) {
+	output := runFixture(t, "units", `func main() {
+	delay := FixtureUnitsDelayUnitValue(250)
+	fmt.Println(delay, delay.Units())
+}`, "--unit-wrappers")
+	if want := "250 milliseconds milliseconds"; output != want {
+		t.Errorf("Wrapped value is %q, want %q", output, want)
+	}
+
+	generated := generateFixture(t, "units")
+	assertNotContains(t, generated, "UnitValue")
+}
+
+func TestUnitsOverride(t *testing.T) {
+	generated := generateFixture(t, "units-override")
+	assertContains(t, generated, "var FixtureDurationType = models.Type{\n")
+	if count := strings.Count(generated, "\"seconds\""); count != 1 {
+		t.Errorf("The UNITS of fixtureJobRuntime are emitted %d times, want once", count)
+	}
+
+	output := runFixture(t, "units-override", `func main() {
+	runtime, timeout := fixtureJobRuntimeNode.Type, fixtureJobTimeoutNode.Type
+	fmt.Printf("%q %q %q\n", runtime.Name, runtime.Units, runtime.Format)
+	fmt.Printf("%q %q %q\n", timeout.Name, timeout.Units, timeout.Format)
+	fmt.Printf("%q %q %q\n", FixtureDurationType.Name, FixtureDurationType.Units, FixtureDurationType.Format)
+}`)
+	want := `"FixtureDuration" "seconds" "d"` + "\n" +
+		`"FixtureDuration" "" "d"` + "\n" +
+		`"FixtureDuration" "" "d"`
+	if output != want {
+		t.Errorf("Unexpected types:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestUnexported(t *testing.T) {
+	generated := generateFixture(t, "keywords", "--unexported", "--tables-map", "--oid-index", "--resolve")
+	assertContains(t, generated,
+		"var FixtureKeywordsMib = FixtureKeywordsMibModule{",
+		"var typeType = models.Type{",
+		"var mapType = models.Type{",
+		"var Tables = map[string]TableInfo{",
+		"var OidIndex = map[string]models.BaseNode{",
+	)
+	assertNotContains(t, generated, "var TypeType = ")
+
+	generated = generateFixture(t, "keywords")
+	assertContains(t, generated, "var TypeType = models.Type{", "var MapType = models.Type{")
+}
+
+func TestKeywords(t *testing.T) {
+	generated := generateFixture(t, "keywords", "--enum-consts", "--enum-strings")
+	assertContains(t, generated,
+		"type Type int64\n",
+		"\tTypePlain Type = 1\n",
+		"func (e Type) String() string {",
+		"type Map int64\n",
+		"\tMapSparse Map = 1\n",
+		"\tType: typeNode,\n",
+		"\tMap:  mapNode,\n",
+	)
+}
+
+func TestPredeclared(t *testing.T) {
+	generated := generateFixture(t, "predeclared", "--enum-consts", "--enum-strings")
+	assertContains(t, generated,
+		"type String int64\n",
+		"\tStringShort String = 1\n",
+		"func (e String) String() string {",
+		"type Error int64\n",
+		"\tErrorFatal Error = 2\n",
+		"\tLen: lenNode,\n",
+		"\tNew: newNode,\n",
+	)
+}
+
+func TestNodeLookup(t *testing.T) {
+	main := `func main() {
+	for _, name := range []string{"fixtureStatusIndex", "fixtureStatusNotification", "FixtureStatusIndex", "ifDescr"} {
+		node, ok := FixtureStatusMib.Node(name)
+		fmt.Printf("%s %t %q\n", name, ok, node.OidFormatted)
+	}
+}`
+	want := `fixtureStatusIndex true "1.3.6.1.4.1.99999.40.4.1.1"
+fixtureStatusNotification true "1.3.6.1.4.1.99999.40.5"
+FixtureStatusIndex false ""
+ifDescr false ""`
+	for _, flags := range [][]string{{"--node-lookup"}, {"--node-lookup", "--lazy"}} {
+		if output := runFixture(t, "status", main, flags...); output != want {
+			t.Errorf("Unexpected lookups with %v:\n%s\nwant:\n%s", flags, output, want)
+		}
+	}
+
+	generated := generateFixture(t, "status")
+	assertNotContains(t, generated, "fixtureStatusMibNodes", ") Node(name string)")
+}
+
+func TestKindAccessors(t *testing.T) {
+	main := `func main() {
+	fmt.Println(len(FixtureStatusMib.Scalars()), len(FixtureStatusMib.Tables()))
+}`
+	// The status fixture has three scalars and one table.
+	if output := runFixture(t, "status", main, "--kind-accessors"); output != "3 1" {
+		t.Errorf("Unexpected counts of scalars and tables: %s", output)
+	}
+
+	generated := generateFixture(t, "status", "--kind-accessors")
+	scalars := strings.Count(generated, "Node = models.ScalarNode{")
+	tables := strings.Count(generated, "Node = models.TableNode{")
+	if scalars != 3 || tables != 1 {
+		t.Errorf("Fixture generated %d scalars and %d tables, want 3 and 1", scalars, tables)
+	}
+
+	generated = generateFixture(t, "status")
+	assertNotContains(t, generated, ") Scalars() []models.ScalarNode", ") Tables() []models.TableNode")
+}
+
+func TestAccessorNames(t *testing.T) {
+	generated := generateFixture(t, "accessors", "--node-lookup", "--kind-accessors")
+	assertContains(t, generated,
+		"func (FixtureAccessorsMibModule) LookupNode(name string) (models.BaseNode, bool) {",
+		"func (FixtureAccessorsMibModule) GetGetScalars() []models.ScalarNode {",
+		"\treturn []models.ScalarNode{\n\t\tnodeNode,\n\t\tscalarsNode,\n\t\tgetScalarsNode,\n\t}\n",
+		"func (FixtureAccessorsMibModule) Tables() []models.TableNode {\n\treturn []models.TableNode{}\n}",
+	)
+}
+
+func TestFileHeaderImports(t *testing.T) {
+	header := fileHeader("mibs", imports{modelsImport: true, "time": true, typesImport: true, "fmt": true})
+	want := `// Code generated by mib2go. DO NOT EDIT.
+package mibs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+`
+	if string(header) != want {
+		t.Errorf("Header is\n%s\nwant\n%s", header, want)
+	}
+	formatted, err := format.Source(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(formatted, header) {
+		t.Errorf("Header differs from its formatted form:\n%s", formatted)
+	}
+
+	// The types file of --cell-oid needs both standard library and gosmi
+	// packages.
+	generated := generateFixture(t, "tables", "--cell-oid")
+	assertContains(t, generated, "import (\n\t\"fmt\"\n\t\"reflect\"\n\n\t\"github.com/sleepinggenius2/gosmi/models\"\n\t\"github.com/sleepinggenius2/gosmi/types\"\n)\n")
+}
+
+// syntheticFile returns a generated file of n node vars, each preceded by a
+// description that looks like the end of a declaration. The vars are
+// separated by no, one or two blank lines in turn, as generated code is.
+func syntheticFile(n int) []byte {
+	b := append([]byte(nil), fileHeader("mibs", imports{modelsImport: true})...)
+	for i := 0; i < n; i++ {
+		b = append(b, strings.Repeat("\n", i%3)...)
+		b = append(b, fmt.Sprintf("/*\nDescription %d.\n}\n*/\nvar node%dNode = models.BaseNode{\n\tName: \"node%d\",\n\tOid: models.Oid{1, 3, 6, 1, %d},\n}\n", i, i, i, i)...)
+	}
+	return append(b, "\n\n"...)
+}
+
+func TestWriteGoFileChunked(t *testing.T) {
+	defer func(size int) { formatChunkSize = size }(formatChunkSize)
+
+	b := syntheticFile(200)
+	want, err := format.Source(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	formatChunkSize = 300
+	var got bytes.Buffer
+	if err := writeGoFile(&got, b); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != string(want) {
+		t.Errorf("Formatting in chunks differs from formatting the whole file:\n%s", got.String())
+	}
+
+	// Every top-level declaration of the generated code ends a chunk of a
+	// single byte.
+	whole := generateFixture(t, "interfaces", "--format-chunk-size", "0")
+	chunked := generateFixture(t, "interfaces", "--format-chunk-size", "1")
+	if chunked != whole {
+		t.Errorf("Generating in chunks differs from generating the whole file:\n%s", chunked)
+	}
+}
+
+func BenchmarkWriteGoFile(b *testing.B) {
+	defer func(size int) { formatChunkSize = size }(formatChunkSize)
+
+	file := syntheticFile(50000)
+	for _, size := range []int{0, 1 << 20} {
+		b.Run(fmt.Sprintf("chunk-size=%d", size), func(b *testing.B) {
+			formatChunkSize = size
+			b.SetBytes(int64(len(file)))
+			for i := 0; i < b.N; i++ {
+				if err := writeGoFile(ioutil.Discard, file); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	generated := generateFixture(t, "canonical", "--canonical", "--enum-consts", "--enum-strings", "--oid-index", "--modules-map", "--language", "--node-info", "--module-oid", "--module-info", "--node-lookup", "--kind-accessors", "--enterprise")
+
+	golden := filepath.Join("..", "testdata", "canonical", "golden", "canonical.go.golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, []byte(generated), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if generated != string(want) {
+		t.Errorf("Generated code differs from %s, rerun with -update if that is intended:\n%s", golden, generated)
+	}
+}
+
+func TestFormatEmptyNames(t *testing.T) {
+	for _, test := range []struct {
+		moduleName string
+		want       string
+	}{
+		{"", ""},
+		{"-", ""},
+		{"A--B", "AB"},
+		{"FOO--BAR-", "FooBar"},
+		{"IF-MIB", "IfMib"},
+	} {
+		if got := formatModuleName(test.moduleName); got != test.want {
+			t.Errorf("formatModuleName(%q) = %q, want %q", test.moduleName, got, test.want)
+		}
+	}
+
+	if got := formatNodeName(""); got != "" {
+		t.Errorf("formatNodeName(\"\") = %q, want \"\"", got)
+	}
+	if got := formatNodeVarName(""); got != "Node" {
+		t.Errorf("formatNodeVarName(\"\") = %q, want \"Node\"", got)
+	}
+}
+
+func TestFormatDigitNames(t *testing.T) {
+	if got := formatModuleName("3GPP-MIB"); got != "X3gppMib" {
+		t.Errorf("formatModuleName(\"3GPP-MIB\") = %q, want \"X3gppMib\"", got)
+	}
+	if got := formatNodeName("3gppFoo"); got != "X3gppFoo" {
+		t.Errorf("formatNodeName(\"3gppFoo\") = %q, want \"X3gppFoo\"", got)
+	}
+	if got := formatNodeVarName("3gppFoo"); got != "x3gppFooNode" {
+		t.Errorf("formatNodeVarName(\"3gppFoo\") = %q, want \"x3gppFooNode\"", got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	output := runFixture(t, "resolve", `func main() {
+	for _, oid := range [][]uint32{
+		{1, 3, 6, 1, 4, 1, 99999, 49, 2, 1, 2, 5},
+		{1, 3, 6, 1, 4, 1, 99999, 49, 2, 1, 2, 10},
+		{1, 3, 6, 1, 4, 1, 99999, 49, 2, 1, 1, 5},
+		{1, 3, 6, 1, 4, 1, 99999, 49, 2, 1, 5, 1, 2},
+		{1, 3, 6, 1, 4, 1, 99999, 49, 2, 1, 2},
+		{1, 3, 6, 1, 4, 1, 99999, 49, 1, 0},
+		{1, 3, 6, 1, 4, 1, 99999, 49, 2, 1, 3, 5},
+		{1, 3, 6, 1, 4, 1, 99999, 49, 2, 1},
+		{1, 3, 6},
+	} {
+		node, index, ok := Resolve(oid)
+		fmt.Println(node.Name, index, ok)
+	}
+}
+`, "--resolve")
+
+	want := strings.Join([]string{
+		"fixtureIfDescr [5] true",
+		"fixtureIfDescr [10] true",
+		"fixtureIfIndex [5] true",
+		"fixtureIfSpeed [1 2] true",
+		"fixtureIfDescr [] true",
+		"fixtureIfNumber [] true",
+		" [] false",
+		" [] false",
+		" [] false",
+	}, "\n")
+	if output != want {
+		t.Errorf("Resolved\n%s\nwant\n%s", output, want)
+	}
+}
+
+func TestSortNodes(t *testing.T) {
+	sorted := []gosmi.SmiNode{
+		{Node: models.Node{Name: "mib", Oid: models.Oid{1, 3, 6, 1, 4, 1, 99999}}},
+		{Node: models.Node{Name: "scalar", Oid: models.Oid{1, 3, 6, 1, 4, 1, 99999, 1}}},
+		{Node: models.Node{Name: "table", Oid: models.Oid{1, 3, 6, 1, 4, 1, 99999, 2}}},
+		{Node: models.Node{Name: "entry", Oid: models.Oid{1, 3, 6, 1, 4, 1, 99999, 2, 1}}},
+		{Node: models.Node{Name: "column", Oid: models.Oid{1, 3, 6, 1, 4, 1, 99999, 2, 1, 1}}},
+		{Node: models.Node{Name: "laterColumn", Oid: models.Oid{1, 3, 6, 1, 4, 1, 99999, 2, 1, 10}}},
+		{Node: models.Node{Name: "laterScalar", Oid: models.Oid{1, 3, 6, 1, 4, 1, 99999, 10}}},
+	}
+
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		nodes := append([]gosmi.SmiNode(nil), sorted...)
+		random.Shuffle(len(nodes), func(i, j int) { nodes[i], nodes[j] = nodes[j], nodes[i] })
+		sortNodes(nodes)
+		for j, node := range nodes {
+			if node.Name != sorted[j].Name {
+				t.Fatalf("Node %d of the sorted nodes is %s, want %s", j, node.Name, sorted[j].Name)
+			}
+		}
+	}
+}
+
+func TestSharedTypesModuleOrder(t *testing.T) {
+	fixtureDir := filepath.Join("..", "testdata", "shared-types")
+	generate := func(moduleNames ...string) string {
+		dir, err := ioutil.TempDir("", "mib2go-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		moduleList := filepath.Join(dir, "modules")
+		err = ioutil.WriteFile(moduleList, []byte(strings.Join(moduleNames, "\n")), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := filepath.Join(dir, "generated")
+		err = generateSources(nil, []string{"--dir", out, "-M", fixtureDir, "--from-file", moduleList, "--enum-consts"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return readGenerated(t, out)
+	}
+
+	generated := generate("FIXTURE-SHARED-A-MIB", "FIXTURE-SHARED-B-MIB")
+	assertContains(t, generated,
+		"var FixtureLevelType = models.Type{",
+		"var DisplayStringType = models.Type{",
+		"\tType: FixtureLevelType,\n",
+	)
+	if strings.Count(generated, "var FixtureLevelType =") != 1 || strings.Count(generated, "type FixtureLevel int64") != 1 {
+		t.Error("Shared type FixtureLevel isn't generated exactly once")
+	}
+	if reversed := generate("FIXTURE-SHARED-B-MIB", "FIXTURE-SHARED-A-MIB"); reversed != generated {
+		t.Errorf("Generated code depends on the order of the modules:\n%s\nreversed:\n%s", generated, reversed)
+	}
+}
+
+func TestOverridePath(t *testing.T) {
+	fixtureDir := filepath.Join("..", "testdata", "override")
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	moduleList := filepath.Join(dir, "modules")
+	err = ioutil.WriteFile(moduleList, []byte("FIXTURE-OVERRIDE-MIB\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generate := func(flags ...string) string {
+		out, err := ioutil.TempDir(dir, "generated")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = generateSources(nil, append([]string{"--dir", out, "--from-file", moduleList}, flags...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return readGenerated(t, out)
+	}
+
+	generated := generate("-M", filepath.Join(fixtureDir, "standard"))
+	assertContains(t, generated, "// The standard definition of the value.\n")
+	assertNotContains(t, generated, "fixtureOverrideVendorValue")
+
+	generated = generate("-M", filepath.Join(fixtureDir, "standard"), "--override-path", filepath.Join(fixtureDir, "vendor"))
+	assertContains(t, generated,
+		"// The vendor definition of the value.\n",
+		"var fixtureOverrideVendorValueNode = models.ScalarNode{\n",
+	)
+	assertNotContains(t, generated, "The standard definition")
+}
+
+func TestOwnOnly(t *testing.T) {
+	imported := filepath.Join("..", "testdata", "own-only", "imported")
+	generated := generateFixture(t, "own-only", "--own-only", "-M", imported)
+	assertContains(t, generated,
+		"// fixtureBaseDeviceIndexNode is a stub for FIXTURE-OWN-BASE-MIB::fixtureBaseDeviceIndex, which is not generated.\n",
+		"\tIndex: []models.ColumnNode{\n\t\tfixtureBaseDeviceIndexNode,\n\t\tfixtureOwnPortIndexNode,\n\t},\n",
+	)
+	if count := strings.Count(generated, "var fixtureBaseDeviceIndexNode ="); count != 1 {
+		t.Errorf("The imported index column is emitted %d times, want once", count)
+	}
+	assertNotContains(t, generated, "fixtureBaseDeviceTableNode", "fixtureBaseDeviceEntryNode", "fixtureBaseDeviceNameNode", "FixtureOwnBaseMib")
+
+	output := runFixture(t, "own-only", `func main() {
+	for _, column := range fixtureOwnPortEntryNode.Index {
+		fmt.Println(column.Name, column.OidFormatted)
+	}
+}`, "--own-only", "-M", imported)
+	want := "fixtureBaseDeviceIndex 1.3.6.1.4.1.99999.63.1.1.1\nfixtureOwnPortIndex 1.3.6.1.4.1.99999.62.1.1.1"
+	if output != want {
+		t.Errorf("Unexpected index:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestUnchangedFilesNotRewritten(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sources := fixtureSources(t, "status")
+	err = generateSources(sources, []string{"--dir", dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for _, filename := range filenames {
+		if err := os.Chtimes(filename, past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = generateSources(sources, []string{"--dir", dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range filenames {
+		info, err := os.Stat(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.ModTime().Equal(past) {
+			t.Errorf("%s was rewritten although it didn't change", filepath.Base(filename))
+		}
+	}
+
+	err = generateSources(sources, []string{"--dir", dir, "--syntax"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dir, "fixture-status-mib.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ModTime().Equal(past) {
+		t.Error("fixture-status-mib.go wasn't rewritten although it changed")
+	}
+}
+
+func TestImplied(t *testing.T) {
+	generated := generateFixture(t, "implied")
+	assertContains(t, generated,
+		"\tIndex: []models.ColumnNode{\n\t\tfixtureImpliedGroupNode,\n\t\tfixtureImpliedNameNode,\n\t},\n\tImplied: true,\n",
+	)
+}
+
+func TestGroups(t *testing.T) {
+	generated := generateFixture(t, "groups")
+	assertContains(t, generated,
+		"\tFixtureGroupsObjectGroup       GroupNode\n",
+		"\tFixtureGroupsNotificationGroup GroupNode\n",
+		"\tObjects: []models.BaseNode{\n\t\tfixtureGroupsCountNode.BaseNode,\n\t\tfixtureGroupsLimitNode.BaseNode,\n\t},\n",
+		"\tObjects: []models.BaseNode{\n\t\tfixtureGroupsLimitReachedNode.BaseNode,\n\t},\n",
+	)
+}
+
+func TestConformance(t *testing.T) {
+	generated := generateFixture(t, "conformance", "--conformance")
+	assertContains(t, generated,
+		"var fixtureComplianceFullNode = ComplianceNode{\n",
+		"\tModules: []string{\"FIXTURE-COMPLIANCE-MIB\"},\n",
+		"\tMandatoryGroups: []models.BaseNode{\n\t\tfixtureComplianceBasicGroupNode.BaseNode,\n\t},\n",
+		"\tOptionalGroups: []models.BaseNode{\n\t\tfixtureComplianceLimitGroupNode.BaseNode,\n\t},\n",
+		"\t\t\tObject: fixtureComplianceLimitNode.BaseNode,\n",
+		"MinValue: 0, MaxValue: 1000}",
+		"\t\t\tMinAccess: types.AccessReadOnly,\n",
+	)
+
+	generated = generateFixture(t, "conformance")
+	assertNotContains(t, generated, "fixtureComplianceFullNode")
+}
+
+func TestResolveImports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fixtureDir := filepath.Join("..", "testdata", "resolve-imports")
+	sources := fixtureSources(t, "resolve-imports")
+	delete(sources, "FIXTURE-TC-MIB")
+
+	err = generateSources(sources, []string{"--dir", dir, "-M", fixtureDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := readGenerated(t, dir)
+	assertContains(t, generated,
+		"var FixtureStateType = models.Type{",
+		"\t\tType: FixtureStateType,\n",
+	)
+	assertNotContains(t, generated, "FixtureTcMib")
+
+	err = generateSources(sources, []string{"--dir", dir, "-M", fixtureDir, "--resolve-imports=false"})
+	if err == nil || !strings.Contains(err.Error(), "FixtureState (FIXTURE-TC-MIB)") {
+		t.Errorf("Expected the type of FIXTURE-TC-MIB to be reported, got %v", err)
+	}
+}
+
+func TestWithImports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sources := fixtureSources(t, "with-imports")
+	sources = map[string]string{"FIXTURE-CHAIN-A-MIB": sources["FIXTURE-CHAIN-A-MIB"]}
+	err = generateSources(sources, []string{"--dir", dir, "-M", filepath.Join("..", "testdata", "with-imports"), "--with-imports"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range []string{"fixture-chain-a-mib.go", "fixture-chain-b-mib.go", "fixture-chain-c-mib.go"} {
+		if _, err := os.Stat(filepath.Join(dir, filename)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestReadModuleList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	moduleList := filepath.Join(dir, "modules")
+	err = ioutil.WriteFile(moduleList, []byte("# Interfaces\nIF-MIB\n\n  \nSNMPv2-MIB # system\n\t# indented\nIF-MIB\r\nIP-MIB"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed, err := readModuleList(moduleList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"IF-MIB", "SNMPv2-MIB", "IF-MIB", "IP-MIB"}; !reflect.DeepEqual(listed, want) {
+		t.Errorf("Listed modules are %v, want %v", listed, want)
+	}
+	if got, want := uniqueArgs(append([]string{"IP-MIB"}, listed...)), []string{"IP-MIB", "IF-MIB", "SNMPv2-MIB"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unique modules are %v, want %v", got, want)
+	}
+
+	_, err = readModuleList(filepath.Join(dir, "missing"))
+	if err == nil {
+		t.Error("Expected a missing module list to be reported")
+	}
+}
+
+func TestFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	moduleList := filepath.Join(dir, "modules")
+	err = ioutil.WriteFile(moduleList, []byte("# The access fixture\nFIXTURE-ACCESS-MIB\n\n# Given as a source as well\nFIXTURE-STATUS-MIB\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "mibs.go")
+	err = generateSources(fixtureSources(t, "status"), []string{"-o", output, "-M", filepath.Join("..", "testdata", "access"), "--from-file", moduleList})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"// Code generated by mib2go. DO NOT EDIT.", "type FixtureStatusMibModule struct", "type FixtureAccessMibModule struct"} {
+		if n := bytes.Count(b, []byte(want)); n != 1 {
+			t.Errorf("Expected %q once, got it %d times", want, n)
+		}
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	outDir := filepath.Join(dir, "mibs")
+	for _, flags := range [][]string{
+		{"--dir", outDir},
+		{"--dir", outDir, "-o", filepath.Join(outDir, "mibs.go")},
+		{"--dir", outDir, "--format", "json"},
+	} {
+		err = generateSources(fixtureSources(t, "status"), append(flags, "--dry-run"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) > 0 {
+			t.Errorf("Expected nothing to be written with %v, got %s", flags, entries[0].Name())
+		}
+	}
+}
+
+func TestFailedRunKeepsOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	moduleList := filepath.Join(dir, "modules")
+	err = ioutil.WriteFile(moduleList, []byte("FIXTURE-UNKNOWN-MIB\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "mibs.go")
+	previous := []byte("//go:generate mib2go -o mibs.go FIXTURE-STATUS-MIB\n\n// Code generated by mib2go. DO NOT EDIT.\npackage mibs\n")
+	err = ioutil.WriteFile(output, previous, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = generateSources(nil, []string{"-o", output, "--from-file", moduleList})
+	if err == nil {
+		t.Fatal("Expected the unknown module to fail the run")
+	}
+	b, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, previous) {
+		t.Errorf("Failed run changed %s to:\n%s", output, b)
+	}
+}
+
+func TestKeepGoing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sources := map[string]string{"FIXTURE-BROKEN-MIB": "FIXTURE-BROKEN-MIB DEFINITIONS ::= BEGIN\nfixtureBroken OBJECT-TYPE\n"}
+	for _, fixture := range []string{"ranges", "units"} {
+		for name, source := range fixtureSources(t, fixture) {
+			sources[name] = source
+		}
+	}
+
+	err = generateSources(sources, []string{"--dir", dir})
+	if err == nil {
+		t.Fatal("Expected the broken module to fail the run")
+	}
+
+	err = generateSources(sources, []string{"--dir", dir, "--keep-going"})
+	if err == nil || !strings.Contains(err.Error(), "1 modules failed:") || !strings.Contains(err.Error(), "FIXTURE-BROKEN-MIB") {
+		t.Errorf("Expected the failure of the broken module only, got %v", err)
+	}
+	for _, filename := range []string{"fixture-ranges-mib.go", "fixture-units-mib.go", "types.go"} {
+		_, err = os.Stat(filepath.Join(dir, filename))
+		if err != nil {
+			t.Errorf("Expected %s to be written: %v", filename, err)
+		}
+	}
+}
+
+func TestModulesMap(t *testing.T) {
+	output := runFixture(t, "with-imports", `func main() {
+	fmt.Println(len(Modules))
+	for _, name := range []string{"FIXTURE-CHAIN-A-MIB", "FIXTURE-CHAIN-B-MIB", "FIXTURE-CHAIN-C-MIB"} {
+		fmt.Printf("%s %T\n", name, Modules[name])
+	}
+}`, "--modules-map")
+	output = strings.Replace(output, "main.", "", -1)
+	want := `3
+FIXTURE-CHAIN-A-MIB FixtureChainAMibModule
+FIXTURE-CHAIN-B-MIB FixtureChainBMibModule
+FIXTURE-CHAIN-C-MIB FixtureChainCMibModule`
+	if output != want {
+		t.Errorf("Unexpected modules:\n%s\nwant:\n%s", output, want)
+	}
+
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = generateSources(fixtureSources(t, "with-imports"), []string{"--dir", dir, "--modules-map"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "modules.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, string(b), "var Modules = map[string]interface{}{\n")
+
+	output = filepath.Join(dir, "mibs.go")
+	err = generateSources(fixtureSources(t, "with-imports"), []string{"-o", output, "--modules-map"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(b, []byte("var Modules = ")); n != 1 {
+		t.Errorf("Expected the Modules map in %s once, got it %d times", output, n)
+	}
+}
+
+func TestOnTypeConflict(t *testing.T) {
+	err := generateSources(fixtureSources(t, "type-conflict"), []string{"--dir", os.DevNull})
+	if err == nil || !strings.Contains(err.Error(), "Foo (FOO-A-MIB, FOO-B-MIB)") {
+		t.Errorf("Expected the conflicting type Foo to be reported with both modules, got %v", err)
+	}
+
+	generated := generateFixture(t, "type-conflict", "--on-type-conflict", "rename")
+	assertContains(t, generated,
+		"var FooAMibFooType = models.Type{\n\tBaseType: types.BaseTypeEnum,",
+		"var FooBMibFooType = models.Type{\n\tBaseType: types.BaseTypeOctetString,",
+		"\tType: FooAMibFooType,\n",
+		"\tType: FooBMibFooType,\n",
+	)
+
+	generated = generateFixture(t, "type-conflict", "--on-type-conflict", "first")
+	assertContains(t, generated, "var FooType = models.Type{\n\tBaseType: types.BaseTypeEnum,")
+	assertNotContains(t, generated, "types.BaseTypeOctetString")
+}
+
+func TestRangeValidators(t *testing.T) {
+	output := runFixture(t, "ranges", `func main() {
+	for _, v := range []int64{0, 1, 10, 11, 19, 20, 30, 31} {
+		fmt.Println(v, FixtureRangesLevelValidate(v))
+	}
+	for _, v := range []string{"abc", "abcd", "abcdefgh", "abcdefghi"} {
+		fmt.Println(v, FixtureRangesCodeValidate([]byte(v)))
+	}
+}`, "--range-validators")
+	want := `0 Value 0 out of range (1..10 | 20..30)
+1 <nil>
+10 <nil>
+11 Value 11 out of range (1..10 | 20..30)
+19 Value 19 out of range (1..10 | 20..30)
+20 <nil>
+30 <nil>
+31 Value 31 out of range (1..10 | 20..30)
+abc Length 3 out of range (SIZE (4..8))
+abcd <nil>
+abcdefgh <nil>
+abcdefghi Length 9 out of range (SIZE (4..8))`
+	if output != want {
+		t.Errorf("Unexpected validation results:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+// TestRangeBounds checks ranges up to the largest unsigned values and down to
+// the smallest Integer64, which SMIv2 can't express for every base type.
+func TestRangeBounds(t *testing.T) {
+	output := runFixture(t, "ranges", `func main() {
+	for _, v := range []uint64{0, 1, 4294967295, 4294967296} {
+		fmt.Println(v, FixtureRangesTotalValidate(v) == nil)
+	}
+}`, "--range-validators")
+	want := `0 false
+1 true
+4294967295 true
+4294967296 false`
+	if output != want {
+		t.Errorf("Unexpected validation results:\n%s\nwant:\n%s", output, want)
+	}
+
+	for _, test := range []struct {
+		r    models.Range
+		want string
+	}{
+		{models.Range{BaseType: types.BaseTypeUnsigned32, MinValue: 0, MaxValue: math.MaxUint32}, "MinValue: 0, MaxValue: 4294967295}"},
+		{models.Range{BaseType: types.BaseTypeUnsigned64, MinValue: 0, MaxValue: -1}, "MinValue: 0, MaxValue: -1 /* 18446744073709551615 */}"},
+		{models.Range{BaseType: types.BaseTypeInteger64, MinValue: math.MinInt64, MaxValue: -1}, "MinValue: -9223372036854775808, MaxValue: -1}"},
+	} {
+		buf := &bytes.Buffer{}
+		generateTypeFields(buf, &models.Type{BaseType: test.r.BaseType, Ranges: []models.Range{test.r}})
+		if !strings.Contains(buf.String(), test.want) {
+			t.Errorf("Expected %q in the fields of %s, got:\n%s", test.want, test.r.BaseType, buf)
+		}
+		_, err := format.Source([]byte("package p\n\nvar x = models.Type{\n" + buf.String() + "}\n"))
+		if err != nil {
+			t.Errorf("Invalid fields of %s: %v", test.r.BaseType, err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	generateRangeValidator(buf, imports{}, "V", "v", &models.Type{
+		BaseType: types.BaseTypeUnsigned64,
+		Ranges:   []models.Range{{BaseType: types.BaseTypeUnsigned64, MinValue: 1, MaxValue: -1}},
+	})
+	assertContains(t, buf.String(), "v >= 1 && v <= 18446744073709551615")
+}
+
+func TestSignedRange(t *testing.T) {
+	generated := generateFixture(t, "signed-range")
+	assertContains(t, generated,
+		"models.Range{BaseType: types.BaseTypeInteger32, MinValue: -40, MaxValue: -1},",
+		"models.Range{BaseType: types.BaseTypeInteger32, MinValue: -128, MaxValue: 127},",
+	)
+
+	output := runFixture(t, "signed-range", `func main() {
+	for _, v := range []int64{-41, -40, -1, 0, 1, 85, 86} {
+		fmt.Println(v, FixtureSignedTemperatureValidate(v) == nil, FixtureOffsetValidate(v) == nil)
+	}
+}`, "--range-validators")
+	want := `-41 false true
+-40 true true
+-1 true true
+0 false true
+1 true true
+85 true true
+86 false true`
+	if output != want {
+		t.Errorf("Unexpected validation results:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestSizeRange(t *testing.T) {
+	generated := generateFixture(t, "size-range")
+	assertContains(t, generated,
+		"models.Range{BaseType: types.BaseTypeUnsigned32, MinValue: 0, MaxValue: 255}, // SIZE\n",
+		"models.Range{BaseType: types.BaseTypeInteger32, MinValue: 0, MaxValue: 255},\n",
+	)
+
+	generated = generateFixture(t, "size-range", "--standalone")
+	assertContains(t, generated,
+		"Range{BaseType: BaseTypeUnsigned32, MinValue: 0, MaxValue: 255, SizeConstraint: true},",
+		"Range{BaseType: BaseTypeInteger32, MinValue: 0, MaxValue: 255},",
+	)
+	assertNotContains(t, generated, "// SIZE")
+}
+
+func TestDuplicateVarNames(t *testing.T) {
+	err := generateSources(fixtureSources(t, "duplicates"), []string{"--dir", os.DevNull})
+	if err == nil || !strings.Contains(err.Error(), "indexNode (FIXTURE-DUP-A-MIB::index, FIXTURE-DUP-B-MIB::index)") {
+		t.Errorf("Expected the duplicate var indexNode to be reported with both modules, got %v", err)
+	}
+
+	generated := generateFixture(t, "duplicates", "--qualify-duplicates")
+	assertContains(t, generated,
+		"var fixtureDupAMibIndexNode = models.ScalarNode{",
+		"var fixtureDupBMibIndexNode = models.ScalarNode{",
+	)
+	assertNotContains(t, generated, "var indexNode ")
+}
+
+func TestBuildTag(t *testing.T) {
+	dir, err := ioutil.TempDir(filepath.Join("..", "testdata"), "generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sources := fixtureSources(t, "status")
+	err = generateSources(sources, []string{"--dir", dir, "--package", "generated", "--build-tag", "mibs && !nomibs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range filenames {
+		b, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(b, []byte("//go:build mibs && !nomibs\n\n// Code generated by mib2go. DO NOT EDIT.\n")) {
+			t.Errorf("%s doesn't start with the build constraint:\n%s", filename, b)
+		}
+		if formatted, err := format.Source(b); err != nil || !bytes.Equal(formatted, b) {
+			t.Errorf("%s isn't formatted: %v", filename, err)
+		}
+	}
+	for _, tags := range []string{"mibs", "mibs,nomibs"} {
+		output, err := exec.Command("go", "build", "-tags", tags, "./"+filepath.ToSlash(dir)).CombinedOutput()
+		if tags == "mibs" && err != nil {
+			t.Errorf("Building with the tag mibs: %v\n%s", err, output)
+		}
+		if tags != "mibs" && !bytes.Contains(output, []byte("build constraints exclude all Go files")) {
+			t.Errorf("Expected the files to be excluded with the tags %s, got %v\n%s", tags, err, output)
+		}
+	}
+
+	err = generateSources(sources, []string{"--dir", dir, "--build-tag", "mibs &&"})
+	if err == nil || !strings.Contains(err.Error(), "Invalid build tag mibs &&") {
+		t.Errorf("Expected the build tag to be rejected, got %v", err)
+	}
+
+	generated := generateFixture(t, "status", "--build-tag", "")
+	assertNotContains(t, generated, "//go:build")
+}
+
+func TestTypesFileInDir(t *testing.T) {
+	sources := fixtureSources(t, "ranges")
+
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	err = generateSources(sources, []string{"-d", "out/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = os.Stat(filepath.Join("out", "types.go"))
+	if err != nil {
+		t.Errorf("Expected types.go in the output directory: %v", err)
+	}
+	_, err = os.Stat("types.go")
+	if !os.IsNotExist(err) {
+		t.Errorf("Expected no types.go in the working directory, got %v", err)
+	}
+}
+
+func TestModuleNameNotFile(t *testing.T) {
+	for arg, want := range map[string]bool{
+		"IF-MIB":          false,
+		"./IF-MIB":        true,
+		"mibs/IF-MIB":     true,
+		"IF-MIB.mib":      true,
+		"IF-MIB.MY":       true,
+		"RFC1213-MIB.txt": true,
+	} {
+		if got := isMibFileArg(arg); got != want {
+			t.Errorf("isMibFileArg(%q) = %v, want %v", arg, got, want)
+		}
+	}
+
+	sources := fixtureSources(t, "status")
+
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// A file in the working directory named like the module, which would
+	// fail to load, must not take the place of the module.
+	err = ioutil.WriteFile("FIXTURE-STATUS-MIB", []byte("not a MIB\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = generateSources(sources, []string{"-d", "out"})
+	if err != nil {
+		t.Fatalf("Expected FIXTURE-STATUS-MIB to be loaded from the search path: %v", err)
+	}
+}
+
+func TestNestedOutDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sources := fixtureSources(t, "ranges")
+	for _, test := range []struct {
+		flags     []string
+		filenames []string
+	}{
+		{nil, []string{"fixture-ranges-mib.go", "types.go"}},
+		{[]string{"--format", "json"}, []string{"fixture-ranges-mib.json"}},
+	} {
+		outDir := filepath.Join(dir, "a", "b", "c")
+		err = generateSources(sources, append([]string{"-d", outDir}, test.flags...))
+		if err != nil {
+			t.Fatalf("Generating into %s with %v: %v", outDir, test.flags, err)
+		}
+		for _, filename := range test.filenames {
+			_, err = os.Stat(filepath.Join(outDir, filename))
+			if err != nil {
+				t.Errorf("Expected %s with %v: %v", filename, test.flags, err)
+			}
+		}
+		os.RemoveAll(filepath.Join(dir, "a"))
+	}
+}
+
+func TestStdoutSplit(t *testing.T) {
+	file, err := ioutil.TempFile("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	stdout := os.Stdout
+	os.Stdout = file
+	err = generateSources(jobsSources(3), []string{"-o", "-", "--stdout-split"})
+	os.Stdout = stdout
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		assertContains(t, string(output), fmt.Sprintf("// --- MODULE: JOBS-TEST-%d-MIB ---\n", i))
+	}
+	if n := strings.Count(string(output), "// --- MODULE: "); n != 3 {
+		t.Errorf("Expected 3 delimiters, got %d", n)
+	}
+	formatted, err := format.Source(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(formatted, output) {
+		t.Error("Expected the delimiters to survive formatting")
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sources := fixtureSources(t, "status")
+	for name, source := range fixtureSources(t, "access") {
+		sources[name] = source
+	}
+	const directive = "//go:generate mib2go generate -o mibs.go FIXTURE-ACCESS-MIB FIXTURE-STATUS-MIB\n"
+	singleFile := filepath.Join(dir, "mibs.go")
+	for run := 0; run < 3; run++ {
+		for _, flags := range [][]string{{"--dir", dir}, {"--dir", dir, "-o", singleFile}} {
+			err = generateSources(sources, flags)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, filename := range filenames {
+			b, err := ioutil.ReadFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := bytes.Count(b, []byte("// Code generated by mib2go. DO NOT EDIT.")); n != 1 {
+				t.Errorf("Run %d: %s has %d headers", run, filename, n)
+			}
+			directives := bytes.Count(b, []byte("//go:generate"))
+			if run == 0 && directives != 0 || run > 0 && (directives != 1 || !bytes.HasPrefix(b, []byte(directive+"\n// Code generated"))) {
+				t.Errorf("Run %d: Unexpected directives in %s:\n%s", run, filename, b)
+			}
+
+			if run == 0 {
+				err = ioutil.WriteFile(filename, append([]byte(directive), b...), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		if len(filenames) != 4 {
+			t.Errorf("Run %d: Expected the files of both modules, the types and the single file, got %v", run, filenames)
+		}
+	}
+}
+
+func TestHeaderFile(t *testing.T) {
+	dir, err := ioutil.TempDir(filepath.Join("..", "testdata"), "generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	headerFile := filepath.Join(dir, "header.tmpl")
+	writeHeader := func(header string) {
+		t.Helper()
+		err := ioutil.WriteFile(headerFile, []byte(header), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	sources := fixtureSources(t, "status")
+
+	writeHeader("//go:build mibs\n\n// Copyright 2017 Example, all rights reserved.\n// Built into package {{.Package}} by mib2go.\n\n// Code generated by mib2go. DO NOT EDIT.")
+	err = generateSources(sources, []string{"--dir", dir, "--package", "generated", "--header-file", headerFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range filenames {
+		b, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(b, []byte("//go:build mibs\n\n// Copyright 2017 Example, all rights reserved.\n// Built into package generated by mib2go.\n\n// Code generated by mib2go. DO NOT EDIT.\n\npackage generated\n")) {
+			t.Errorf("%s doesn't start with the header:\n%s", filename, b)
+		}
+		if formatted, err := format.Source(b); err != nil || !bytes.Equal(formatted, b) {
+			t.Errorf("%s isn't formatted: %v", filename, err)
+		}
+	}
+	output, err := exec.Command("go", "build", "-tags", "mibs", "./"+filepath.ToSlash(dir)).CombinedOutput()
+	if err != nil {
+		t.Errorf("Building with the tag mibs: %v\n%s", err, output)
+	}
+
+	for header, want := range map[string]string{
+		"/* unterminated":   "doesn't result in valid Go",
+		"// {{.Package":     "Parsing header file",
+		"// {{.Generator}}": "Executing header file",
+	} {
+		writeHeader(header)
+		err = generateSources(sources, []string{"--dir", dir, "--header-file", headerFile})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected the header %q to be rejected with %q, got %v", header, want, err)
+		}
+	}
+}
+
+func TestFlattenTables(t *testing.T) {
+	generated := generateFixture(t, "interfaces", "--flatten-tables")
+	assertContains(t, generated,
+		"// IfTableColumns is ifTable with direct access to its columns.\ntype IfTableColumns struct {\n\tmodels.TableNode\n\tIfIndex models.ColumnNode\n\tIfDescr models.ColumnNode\n}\n",
+		"\tIfTable             IfTableColumns\n",
+	)
+
+	for _, test := range []struct {
+		table string
+		flags []string
+	}{
+		{"FixtureIfMib.IfTable()", []string{"--flatten-tables", "--lazy"}},
+		{"FixtureIfMib.IfTable", []string{"--flatten-tables"}},
+	} {
+		output := runFixture(t, "interfaces", fmt.Sprintf(`func main() {
+	table := %s
+	fmt.Println(table.Name, table.IfIndex.Name, table.IfDescr.Name, table.IfDescr.OidFormatted)
+}`, test.table), test.flags...)
+		if want := "ifTable ifIndex ifDescr 1.3.6.1.4.1.99999.59.2.1.2"; output != want {
+			t.Errorf("Flattened table with %v is %s, want %s", test.flags, output, want)
+		}
+	}
+
+	table := gosmi.SmiNode{Node: models.Node{Name: "ifTable", Kind: types.NodeTable}}
+	var columns []gosmi.SmiNode
+	for _, name := range []string{"ifIndex", "ifTable", "row", "columns", "oid"} {
+		columns = append(columns, gosmi.SmiNode{Node: models.Node{Name: name, Kind: types.NodeColumn}})
+	}
+	got := flatColumnFieldNames(table, columns)
+	want := []string{"IfIndex", "IfTableColumn", "RowColumn", "ColumnsColumn", "OidColumn"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the fields %v, got %v", want, got)
+	}
+}
+
+func TestPreserveAcronyms(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sources := map[string]string{"IP-MIB": `IP-MIB DEFINITIONS ::= BEGIN
+IMPORTS OBJECT-TYPE, Integer32, enterprises FROM SNMPv2-SMI;
+ip OBJECT IDENTIFIER ::= { enterprises 99999 60 }
+ipForwarding OBJECT-TYPE
+    SYNTAX Integer32
+    MAX-ACCESS read-only
+    STATUS current
+    DESCRIPTION "Whether datagrams are forwarded."
+    ::= { ip 1 }
+END
+`}
+	for _, test := range []struct {
+		flags []string
+		name  string
+	}{
+		{nil, "IpMib"},
+		{[]string{"--preserve-acronyms"}, "IPMib"},
+		{[]string{"--preserve-acronyms", "--acronyms", "MIB"}, "IpMIB"},
+	} {
+		err = generateSources(sources, append([]string{"--dir", dir}, test.flags...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		generated := readGenerated(t, dir)
+		assertContains(t, generated,
+			"type "+test.name+"Module struct {\n",
+			"var "+test.name+" = "+test.name+"Module{\n",
+		)
+	}
+
+	acronyms = []string{"IP", "TCP", "HTTP", "ID", "OID"}
+	preserveAcronyms = true
+	defer func() { preserveAcronyms = false }()
+	for name, want := range map[string]string{"TCP-MIB": "TCPMib", "SNMPv2-MIB": "Snmpv2Mib", "ip-forward-mib": "IPForwardMib"} {
+		if got := formatModuleName(name); got != want {
+			t.Errorf("Expected %s to be formatted as %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestOidComments(t *testing.T) {
+	gosmi.Init()
+	gosmi.AppendPath(filepath.Join("..", "testdata", "interfaces"))
+	_, err := gosmi.LoadModule("FIXTURE-IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ifNumber, err := gosmi.GetNode("ifNumber")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ifDescr, err := gosmi.GetNode("ifDescr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scalarOid, columnOid := ifNumber.RenderNumeric()+".0", ifDescr.RenderNumeric()
+	gosmi.Exit()
+
+	generated := generateFixture(t, "interfaces", "--oid-comments", "--lazy")
+	assertContains(t, generated,
+		"func ifNumberNode() models.ScalarNode { // "+scalarOid+"\n",
+		"func ifDescrNode() models.ColumnNode { // "+columnOid+"\n",
+	)
+
+	generated = generateFixture(t, "interfaces", "--oid-comments")
+	assertContains(t, generated,
+		"var ifNumberNode = models.ScalarNode{ // "+scalarOid+"\n",
+		"The description of an interface.\n*/\nvar ifDescrNode = models.ColumnNode{ // "+columnOid+"\n",
+	)
+}
+
+func TestNodeFilters(t *testing.T) {
+	for _, pattern := range []string{"[a", "/(/"} {
+		err := generateSources(fixtureSources(t, "interfaces"), []string{"--only", pattern})
+		if err == nil {
+			t.Errorf("Expected the pattern %s to be rejected", pattern)
+		}
+	}
+
+	for _, flags := range [][]string{{"--only", "if*"}, {"--exclude", "/^fixture/"}} {
+		generated := generateFixture(t, "interfaces", flags...)
+		assertContains(t, generated, "type FixtureIfMibModule struct {\n\tIfNumber models.ScalarNode\n\tIfTable  models.TableNode\n\tIfEntry  models.RowNode\n\tIfIndex  models.ColumnNode\n\tIfDescr  models.ColumnNode\n}\n")
+		assertNotContains(t, generated, "fixtureIfLastChange")
+	}
+}
+
+func TestDependencyClosure(t *testing.T) {
+	generated := generateFixture(t, "interfaces", "--only", "ifTable")
+	assertContains(t, generated,
+		"type FixtureIfMibModule struct {\n\tIfTable models.TableNode\n\tIfEntry models.RowNode\n\tIfIndex models.ColumnNode\n\tIfDescr models.ColumnNode\n}\n",
+		"var ifIndexNode = ",
+		"\t\tType: DisplayStringType,\n",
+		"var DisplayStringType = models.Type{\n",
+	)
+	assertNotContains(t, generated, "ifNumber", "fixtureIfLastChange", "fixtureIfLinkDown")
+
+	generated = generateFixture(t, "interfaces", "--only", "fixtureIfLinkDown")
+	assertContains(t, generated, "type FixtureIfMibModule struct {\n\tIfIndex           models.ColumnNode\n\tIfDescr           models.ColumnNode\n\tFixtureIfLinkDown models.NotificationNode\n}\n")
+}
+
+func TestReferencedImports(t *testing.T) {
+	// The body only mentions the packages in a comment and a string, so
+	// importing them would fail the build.
+	imports := imports{modelsImport: true, typesImport: true, "time": true}
+	body := "// Descriptions of models.Type.\nvar Descriptions = map[string]string{\"1.3\": \"types.BaseTypeEnum\"}\n\n"
+	output := runGenerated(t, imports, body, "func main() {\n\tfmt.Println(len(Descriptions))\n}\n")
+	if output != "1" {
+		t.Errorf("Expected a file referencing neither package to run, got %s", output)
+	}
+	if !imports[modelsImport] || !imports[typesImport] {
+		t.Errorf("Expected the imports given to be left as they are, got %v", imports)
+	}
+
+	// descriptions.go is generated without imports, as it only holds strings.
+	generated := generateFixture(t, "interfaces", "--descriptions-file")
+	assertContains(t, generated, "package generated\n\n// Descriptions maps the OIDs of nodes and modules to their descriptions.\n")
+}
@@ -0,0 +1,77 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var (
+	interruptMu    sync.Mutex
+	interruptPaths = map[string]int{}
+)
+
+// removeOnInterrupt registers path, a temporary directory or an output file
+// still being written, to be removed if the process is interrupted before the
+// returned function is called. Deferred calls don't run when exiting on a
+// signal, so this is what keeps an interrupt from leaving them behind.
+func removeOnInterrupt(path string) (done func()) {
+	interruptMu.Lock()
+	defer interruptMu.Unlock()
+	interruptPaths[path]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			interruptMu.Lock()
+			defer interruptMu.Unlock()
+			interruptPaths[path]--
+			if interruptPaths[path] <= 0 {
+				delete(interruptPaths, path)
+			}
+		})
+	}
+}
+
+// handleInterrupts removes the paths registered with removeOnInterrupt and
+// exits on SIGINT or SIGTERM.
+func handleInterrupts() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+
+		// The lock is never released, which keeps anything from registering
+		// or finishing while exiting.
+		interruptMu.Lock()
+		for path := range interruptPaths {
+			if err := os.RemoveAll(path); err != nil {
+				log.Printf("Removing %s: %v\n", path, err)
+			}
+		}
+		log.Printf("Interrupted by %s, removed temporary files and partial output\n", sig)
+		os.Exit(1)
+	}()
+}
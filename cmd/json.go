@@ -0,0 +1,145 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+type jsonNode struct {
+	Module       string     `json:"module"`
+	Name         string     `json:"name"`
+	Oid          models.Oid `json:"oid"`
+	OidFormatted string     `json:"oidFormatted"`
+	Kind         string     `json:"kind"`
+	Type         *jsonType  `json:"type,omitempty"`
+	Description  string     `json:"description,omitempty"`
+}
+
+type jsonType struct {
+	Name     string           `json:"name"`
+	BaseType string           `json:"baseType"`
+	Enum     map[int64]string `json:"enum,omitempty"`
+	Ranges   []jsonRange      `json:"ranges,omitempty"`
+	Units    string           `json:"units,omitempty"`
+}
+
+type jsonRange struct {
+	Min  int64 `json:"min"`
+	Max  int64 `json:"max"`
+	Size bool  `json:"size,omitempty"`
+}
+
+// generateJSON writes the nodes of each module as a JSON array sorted by OID,
+// to a file per module or all of them into a single array to out.
+func generateJSON(modules []gosmi.SmiModule, out io.Writer) error {
+	var all []jsonNode
+	for _, module := range modules {
+		nodes := moduleJSONNodes(module)
+		if out != nil {
+			all = append(all, nodes...)
+			continue
+		}
+
+		filename := path.Join(outDir, strings.ToLower(module.Name)+".json")
+		err := writeJSONFile(filename, nodes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	sortJSONNodes(all)
+	return writeJSON(out, all)
+}
+
+func moduleJSONNodes(module gosmi.SmiModule) []jsonNode {
+	nodes := []jsonNode{}
+	for _, node := range moduleNodes(module) {
+		if node.Kind&allowedNodeKinds == 0 {
+			continue
+		}
+
+		oid, oidFormatted, _ := instanceOid(node)
+		jsonNode := jsonNode{
+			Module:       module.Name,
+			Name:         node.Name,
+			Oid:          oid,
+			OidFormatted: oidFormatted,
+			Kind:         node.Kind.String(),
+			Description:  string(normalizeEncoding([]byte(node.Description))),
+		}
+		if node.Kind&(types.NodeColumn|types.NodeScalar) > 0 && node.Type != nil {
+			jsonNode.Type = &jsonType{
+				Name:     node.Type.Name,
+				BaseType: node.Type.BaseType.String(),
+				Units:    node.Type.Units,
+			}
+			if node.Type.Enum != nil {
+				jsonNode.Type.Enum = node.Type.Enum.Values
+			}
+			for _, typeRange := range node.Type.Ranges {
+				jsonNode.Type.Ranges = append(jsonNode.Type.Ranges, jsonRange{Min: typeRange.MinValue, Max: typeRange.MaxValue, Size: isSizeConstrained(node.Type)})
+			}
+		}
+		nodes = append(nodes, jsonNode)
+	}
+
+	sortJSONNodes(nodes)
+	return nodes
+}
+
+func sortJSONNodes(nodes []jsonNode) {
+	sort.SliceStable(nodes, func(i, j int) bool { return oidLess(nodes[i].Oid, nodes[j].Oid) })
+}
+
+func writeJSONFile(filename string, nodes []jsonNode) error {
+	buf := &bytes.Buffer{}
+	err := writeJSON(buf, nodes)
+	if err != nil {
+		return err
+	}
+
+	return writeOutputFile(filename, buf.Bytes())
+}
+
+func writeJSON(out io.Writer, nodes []jsonNode) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "\t")
+	err := encoder.Encode(nodes)
+	if err != nil {
+		return errors.Wrap(err, "Encoding JSON")
+	}
+	return nil
+}
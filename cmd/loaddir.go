@@ -0,0 +1,179 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	moduleDefinitionPattern = regexp.MustCompile(`([A-Za-z][-A-Za-z0-9]*)\s+DEFINITIONS(?:\s+(?:IMPLICIT|EXPLICIT|AUTOMATIC)\s+TAGS)?\s*::=\s*BEGIN\b`)
+	importsPattern          = regexp.MustCompile(`(?s)\bIMPORTS\b(.*?);`)
+	importFromPattern       = regexp.MustCompile(`\bFROM\s+([A-Za-z][-A-Za-z0-9]*)`)
+)
+
+// mibFile is a file found by --load-dir with the modules it defines and the
+// modules those import.
+type mibFile struct {
+	path    string
+	modules []string
+	imports []string
+}
+
+// loadDirArgs walks dir for MIB files, recognized by the module definitions
+// in them rather than by their extension, and returns the args to load them
+// with. Files are ordered so that the modules they import from other files
+// in dir are loaded first, as libsmi only finds modules by name in files
+// named after them. Every file is followed by the names of the modules it
+// defines, so files defining more than one module are generated entirely.
+func loadDirArgs(dir string) ([]string, error) {
+	var files []*mibFile
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if file := scanMibFile(path, b); file != nil {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Walking directory %s", dir)
+	}
+
+	definedIn := make(map[string]*mibFile)
+	for _, file := range files {
+		for _, module := range file.modules {
+			if other, ok := definedIn[module]; ok {
+				log.Printf("Module %s is defined in %s and %s, using the former\n", module, other.path, file.path)
+				continue
+			}
+			definedIn[module] = file
+		}
+	}
+
+	var args []string
+	visited := make(map[*mibFile]bool)
+	var visit func(file *mibFile)
+	visit = func(file *mibFile) {
+		if visited[file] {
+			return
+		}
+		// Marking files before their imports keeps import cycles from
+		// recursing forever, libsmi resolves them once either is loaded.
+		visited[file] = true
+		for _, module := range file.imports {
+			if imported, ok := definedIn[module]; ok {
+				visit(imported)
+			}
+		}
+		path := file.path
+		if !isMibFileArg(path) {
+			// Files right in the working directory, as walking "." finds
+			// them, would otherwise be taken for the names of modules.
+			path = "." + string(filepath.Separator) + path
+		}
+		args = append(append(args, path), file.modules...)
+	}
+	for _, file := range files {
+		visit(file)
+	}
+
+	return args, nil
+}
+
+// scanMibFile returns the modules defined in the file at path with contents
+// b, or nil if it doesn't define any.
+func scanMibFile(path string, b []byte) *mibFile {
+	text := stripMibComments(normalizeEncoding(b))
+	definitions := moduleDefinitionPattern.FindAllSubmatchIndex(text, -1)
+	if len(definitions) == 0 {
+		return nil
+	}
+
+	file := &mibFile{path: path}
+	for i, definition := range definitions {
+		file.modules = append(file.modules, string(text[definition[2]:definition[3]]))
+
+		body := text[definition[1]:]
+		if i < len(definitions)-1 {
+			body = text[definition[1]:definitions[i+1][0]]
+		}
+		imports := importsPattern.FindSubmatch(body)
+		if imports == nil {
+			continue
+		}
+		for _, from := range importFromPattern.FindAllSubmatch(imports[1], -1) {
+			file.imports = append(file.imports, string(from[1]))
+		}
+	}
+	return file
+}
+
+// stripMibComments blanks out the comments and quoted strings of a MIB, so
+// descriptions mentioning module definitions or imports aren't taken for
+// them.
+func stripMibComments(b []byte) []byte {
+	stripped := make([]byte, len(b))
+	copy(stripped, b)
+	inString, inComment := false, false
+	for i := 0; i < len(stripped); i++ {
+		switch {
+		case inString:
+			if stripped[i] == '"' {
+				inString = false
+			}
+			stripped[i] = ' '
+		case inComment:
+			if stripped[i] == '\n' {
+				inComment = false
+				continue
+			}
+			if stripped[i] == '-' && i+1 < len(stripped) && stripped[i+1] == '-' {
+				inComment = false
+				stripped[i+1] = ' '
+			}
+			stripped[i] = ' '
+		case stripped[i] == '"':
+			inString = true
+			stripped[i] = ' '
+		case stripped[i] == '-' && i+1 < len(stripped) && stripped[i+1] == '-':
+			inComment = true
+			stripped[i], stripped[i+1] = ' ', ' '
+			i++
+		}
+	}
+	return stripped
+}
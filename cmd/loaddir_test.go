@@ -0,0 +1,88 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadDirArgs(t *testing.T) {
+	dir := filepath.Join("..", "testdata", "load-dir")
+	args, err := loadDirArgs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(dir, "base.mib"), "FIXTURE-DIR-BASE-MIB",
+		filepath.Join(dir, "vendor", "middle.my"), "FIXTURE-DIR-MIDDLE-MIB",
+		filepath.Join(dir, "a-top.txt"), "FIXTURE-DIR-TOP-MIB",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("Args are %v, want %v", args, want)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = generateSources(nil, []string{"--dir", dir, "--load-dir", filepath.Join("..", "testdata", "load-dir")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range []string{"fixture-dir-base-mib.go", "fixture-dir-middle-mib.go", "fixture-dir-top-mib.go"} {
+		if _, err := os.Stat(filepath.Join(dir, filename)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestScanMibFile(t *testing.T) {
+	file := scanMibFile("modules.mib", []byte(`-- A-MIB DEFINITIONS ::= BEGIN
+B-MIB DEFINITIONS ::= BEGIN
+IMPORTS x FROM A-MIB y FROM C-MIB;
+d OBJECT-TYPE DESCRIPTION "IMPORTS z FROM Q-MIB; E-MIB DEFINITIONS ::= BEGIN"
+END
+D-MIB DEFINITIONS -- comment -- ::= BEGIN
+IMPORTS a FROM B-MIB;
+END
+`))
+	if file == nil {
+		t.Fatal("Expected modules.mib to be recognized as a MIB file")
+	}
+	if want := []string{"B-MIB", "D-MIB"}; !reflect.DeepEqual(file.modules, want) {
+		t.Errorf("Modules are %v, want %v", file.modules, want)
+	}
+	if want := []string{"A-MIB", "C-MIB", "B-MIB"}; !reflect.DeepEqual(file.imports, want) {
+		t.Errorf("Imports are %v, want %v", file.imports, want)
+	}
+
+	if file := scanMibFile("README", []byte("Not a MIB.\n")); file != nil {
+		t.Errorf("Expected README not to be recognized as a MIB file, got %v", file)
+	}
+}
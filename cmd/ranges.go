@@ -0,0 +1,132 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// rangeValueTypes are the Go types range validators take values of by base
+// type. Octet strings and BITS are validated by their length instead.
+var rangeValueTypes = map[types.BaseType]string{
+	types.BaseTypeInteger32:   "int64",
+	types.BaseTypeInteger64:   "int64",
+	types.BaseTypeEnum:        "int64",
+	types.BaseTypeUnsigned32:  "uint64",
+	types.BaseTypeUnsigned64:  "uint64",
+	types.BaseTypeOctetString: "[]byte",
+	types.BaseTypeBits:        "[]byte",
+}
+
+// isSizeConstrained reports whether the ranges of t constrain the length of
+// its values rather than the values themselves.
+func isSizeConstrained(t *models.Type) bool {
+	return t.BaseType == types.BaseTypeOctetString || t.BaseType == types.BaseTypeBits
+}
+
+// generateRangeValidator emits a function named name checking values of the
+// type t, described by what, against its ranges.
+func generateRangeValidator(buf io.Writer, imports imports, name string, what string, t *models.Type) {
+	valueType, ok := rangeValueTypes[t.BaseType]
+	if !ok || len(t.Ranges) == 0 {
+		return
+	}
+
+	value, subject := "v", "Value"
+	if isSizeConstrained(t) {
+		value, subject = "len(v)", "Length"
+	}
+	conditions := make([]string, len(t.Ranges))
+	for i, typeRange := range t.Ranges {
+		min, max := formatRangeBound(valueType, typeRange.MinValue), formatRangeBound(valueType, typeRange.MaxValue)
+		switch {
+		case min == max:
+			conditions[i] = fmt.Sprintf("%s == %s", value, min)
+		case min == "0" && valueType != "int64":
+			// Unsigned values and lengths can't be below 0 anyway.
+			conditions[i] = fmt.Sprintf("%s <= %s", value, max)
+		default:
+			conditions[i] = fmt.Sprintf("%s >= %s && %s <= %s", value, min, value, max)
+		}
+	}
+
+	imports.add("fmt")
+	fmt.Fprintf(buf, "// %s returns an error if v is out of the range %s of %s.\n", name, formatRanges(t), what)
+	fmt.Fprintf(buf, "func %s(v %s) error {\n", name, valueType)
+	fmt.Fprintf(buf, "\tif %s {\n", strings.Join(conditions, " || "))
+	fmt.Fprintf(buf, "\t\treturn nil\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\treturn fmt.Errorf(%q, %s)\n", subject+" %d out of range "+formatRanges(t), value)
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// formatRangeValue renders a bound of a range of the given base type as the
+// int64 literal of the Range fields. Unsigned64 bounds beyond the range of
+// int64 can only be held as the negative int64 with the same bits, so their
+// unsigned value is added in a comment.
+func formatRangeValue(baseType types.BaseType, value int64) string {
+	if baseType == types.BaseTypeUnsigned64 && value < 0 {
+		return fmt.Sprintf("%d /* %d */", value, uint64(value))
+	}
+	return strconv.FormatInt(value, 10)
+}
+
+// formatRangeBoundValue renders a bound of a range of the given base type as
+// the value it stands for, signed unless the base type is unsigned.
+func formatRangeBoundValue(baseType types.BaseType, value int64) string {
+	if baseType == types.BaseTypeUnsigned64 {
+		return strconv.FormatUint(uint64(value), 10)
+	}
+	return strconv.FormatInt(value, 10)
+}
+
+// formatRangeBound renders bound as a literal compared to values of valueType.
+// Unsigned bounds are the bits of the unsigned value, which only differ for
+// those beyond the range of int64.
+func formatRangeBound(valueType string, bound int64) string {
+	if valueType == "uint64" {
+		return fmt.Sprint(uint64(bound))
+	}
+	return fmt.Sprint(bound)
+}
+
+// formatRanges renders the ranges of t in SMI notation, like (1..10 | 20).
+func formatRanges(t *models.Type) string {
+	valueType := rangeValueTypes[t.BaseType]
+	parts := make([]string, len(t.Ranges))
+	for i, typeRange := range t.Ranges {
+		min, max := formatRangeBound(valueType, typeRange.MinValue), formatRangeBound(valueType, typeRange.MaxValue)
+		parts[i] = min
+		if min != max {
+			parts[i] += ".." + max
+		}
+	}
+	if isSizeConstrained(t) {
+		return "(SIZE (" + strings.Join(parts, " | ") + "))"
+	}
+	return "(" + strings.Join(parts, " | ") + ")"
+}
@@ -0,0 +1,105 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"bufio"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// renames maps the MIB names of nodes and modules given with --rename-file to
+// the Go names they are generated with instead. renamed records the names a
+// rename was used for, to warn about those that never were.
+var (
+	renames map[string]string
+	renamed map[string]bool
+)
+
+// readRenameFile reads the mibName=GoName lines of a --rename-file. Blank lines
+// and everything following a # are ignored.
+func readRenameFile(filename string) (map[string]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Opening rename file %s", filename)
+	}
+	defer file.Close()
+
+	names := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("Invalid rename %q in %s:%d, expected mibName=GoName", line, filename, lineNumber)
+		}
+		mibName, goName := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if mibName == "" || !token.IsIdentifier(goName) {
+			return nil, errors.Errorf("Invalid rename %q in %s:%d, expected mibName=GoName", line, filename, lineNumber)
+		}
+		if other, ok := names[mibName]; ok && other != goName {
+			return nil, errors.Errorf("%s is renamed to both %s and %s in %s", mibName, other, goName, filename)
+		}
+		names[mibName] = goName
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "Reading rename file %s", filename)
+	}
+
+	return names, nil
+}
+
+// renameOf returns the Go name a node or module named mibName is renamed to
+// with --rename-file, and whether it is renamed.
+func renameOf(mibName string) (string, bool) {
+	goName, ok := renames[mibName]
+	if ok {
+		renamed[mibName] = true
+	}
+	return goName, ok
+}
+
+// warnUnusedRenames logs the renames of --rename-file that didn't match any
+// node or module generated.
+func warnUnusedRenames() {
+	var unused []string
+	for mibName := range renames {
+		if !renamed[mibName] {
+			unused = append(unused, mibName)
+		}
+	}
+	sort.Strings(unused)
+	for _, mibName := range unused {
+		log.Printf("Rename of %s to %s didn't match any node or module\n", mibName, renames[mibName])
+	}
+}
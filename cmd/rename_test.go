@@ -0,0 +1,78 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenameFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	invalidFile := filepath.Join(dir, "invalid.txt")
+	err = ioutil.WriteFile(invalidFile, []byte("ifDescr InterfaceDescription\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = readRenameFile(invalidFile)
+	if err == nil {
+		t.Error("Expected a line without = to be rejected")
+	}
+
+	renameFile := filepath.Join(dir, "renames.txt")
+	err = ioutil.WriteFile(renameFile, []byte("# house style\nifDescr = InterfaceDescription\n\nFIXTURE-IF-MIB=Interfaces\nnever=Used\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logs := &bytes.Buffer{}
+	log.SetOutput(logs)
+	defer log.SetOutput(os.Stderr)
+
+	generated := generateFixture(t, "interfaces", "--rename-file", renameFile, "--flatten-tables")
+	assertContains(t, generated,
+		"type InterfacesModule struct {\n",
+		"\tInterfaceDescription models.ColumnNode\n",
+		"\t\tInterfaceDescription: interfaceDescriptionNode,\n",
+		"var interfaceDescriptionNode = models.ColumnNode{\n",
+	)
+	assertNotContains(t, generated, "IfDescr", "ifDescrNode")
+	if !strings.Contains(logs.String(), "Rename of never to Used didn't match any node or module") || strings.Contains(logs.String(), "Rename of ifDescr") {
+		t.Errorf("Expected a warning about the unused rename only, got:\n%s", logs)
+	}
+
+	output := runFixture(t, "interfaces", `func main() {
+	fmt.Println(Interfaces.InterfaceDescription.Name, Interfaces.IfIndex.Name)
+}`, "--rename-file", renameFile)
+	if output != "ifDescr ifIndex" {
+		t.Errorf("Expected the renamed node to keep its MIB name, got %s", output)
+	}
+}
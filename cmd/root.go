@@ -25,7 +25,9 @@ import (
 	"os"
 
 	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
@@ -44,6 +46,8 @@ var RootCmd = &cobra.Command{
 // Execute adds all child commands to the root command sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	handleInterrupts()
+
 	if err := RootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -85,6 +89,34 @@ func initConfig() {
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
+
+// applyConfig sets the flags of cmd that were not given on the command line
+// from the section of the config file named after cmd, so flags take
+// precedence over the config file, which takes precedence over the defaults.
+func applyConfig(cmd *cobra.Command) error {
+	return applyConfigSection(viper.GetViper(), cmd.Name(), cmd.Flags())
+}
+
+// applyConfigSection sets the flags in flags that were not given from the
+// given section of config.
+func applyConfigSection(config *viper.Viper, section string, flags *pflag.FlagSet) (err error) {
+	flags.VisitAll(func(flag *pflag.Flag) {
+		key := section + "." + flag.Name
+		if err != nil || flag.Changed || !config.IsSet(key) {
+			return
+		}
+
+		if value, ok := flag.Value.(pflag.SliceValue); ok {
+			err = value.Replace(config.GetStringSlice(key))
+		} else {
+			err = flag.Value.Set(config.GetString(key))
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "Setting %s from config file", key)
+		}
+	})
+	return
+}
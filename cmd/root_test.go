@@ -0,0 +1,111 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func TestApplyConfigSection(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, ".mib2go.yaml")
+	err = ioutil.WriteFile(configFile, []byte(`generate:
+  package: configured
+  dir: configured
+  path: [a, b]
+  exclude: c
+  single-file: true
+tree:
+  depth: 2
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := viper.New()
+	config.SetConfigFile(configFile)
+	err = config.ReadInConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		packageFlag, dirFlag          string
+		pathFlag, excludeFlag         []string
+		singleFileFlag, canonicalFlag bool
+	)
+	flags := pflag.NewFlagSet("generate", pflag.ContinueOnError)
+	flags.StringVar(&packageFlag, "package", "default", "")
+	flags.StringVar(&dirFlag, "dir", "default", "")
+	flags.StringSliceVar(&pathFlag, "path", nil, "")
+	flags.StringSliceVar(&excludeFlag, "exclude", nil, "")
+	flags.BoolVar(&singleFileFlag, "single-file", false, "")
+	flags.BoolVar(&canonicalFlag, "canonical", false, "")
+	err = flags.Parse([]string{"--dir", "flag"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = applyConfigSection(config, "generate", flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packageFlag != "configured" {
+		t.Errorf("Expected the package of the config file, got %s", packageFlag)
+	}
+	if dirFlag != "flag" {
+		t.Errorf("Expected the flag to take precedence over the config file, got %s", dirFlag)
+	}
+	if !reflect.DeepEqual(pathFlag, []string{"a", "b"}) || !reflect.DeepEqual(excludeFlag, []string{"c"}) {
+		t.Errorf("Expected the slices of the config file, got %v and %v", pathFlag, excludeFlag)
+	}
+	if !singleFileFlag || canonicalFlag {
+		t.Errorf("Expected only single-file to be set, got %t and %t", singleFileFlag, canonicalFlag)
+	}
+
+	err = ioutil.WriteFile(configFile, []byte("generate:\n  single-file: maybe\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.ReadInConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = applyConfigSection(config, "generate", pflag.NewFlagSet("generate", pflag.ContinueOnError))
+	if err != nil {
+		t.Errorf("Expected keys without a flag to be ignored, got %v", err)
+	}
+	err = applyConfigSection(config, "generate", flags)
+	if err == nil {
+		t.Error("Expected an invalid value to be rejected")
+	}
+}
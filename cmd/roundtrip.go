@@ -0,0 +1,204 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// verifyRoundTrip parses the code generated for module in body and compares
+// the node vars found there to the nodes they were generated from, reporting
+// every node whose name, OID, type or, with --node-info, access doesn't
+// survive the round trip.
+func verifyRoundTrip(module gosmi.SmiModule, shared *sharedDecls, body []byte) error {
+	file, err := parser.ParseFile(token.NewFileSet(), "", append([]byte("package p\n"), body...), 0)
+	if err != nil {
+		return errors.Wrapf(err, "Parsing code generated for module %s", module.Name)
+	}
+
+	literals := make(map[string]*ast.CompositeLit)
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			if len(valueSpec.Names) != 1 || len(valueSpec.Values) != 1 {
+				continue
+			}
+			if literal, ok := valueSpec.Values[0].(*ast.CompositeLit); ok {
+				literals[valueSpec.Names[0].Name] = literal
+			}
+		}
+	}
+
+	// With --lazy, the literals are assigned to the value behind the accessor.
+	ast.Inspect(file, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return true
+		}
+		name, ok := assign.Lhs[0].(*ast.Ident)
+		literal, isLiteral := assign.Rhs[0].(*ast.CompositeLit)
+		if ok && isLiteral && strings.HasSuffix(name.Name, "Value") {
+			literals[strings.TrimSuffix(name.Name, "Value")] = literal
+		}
+		return true
+	})
+
+	var mismatches []string
+	for _, node := range moduleNodes(module) {
+		if node.Kind&allowedNodeKinds == 0 {
+			continue
+		}
+
+		varName := shared.nodeVarName(module.Name, node.Name)
+		literal, ok := literals[varName]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s::%s: var %s not found", module.Name, node.Name, varName))
+			continue
+		}
+
+		for _, mismatch := range compareNodeLiteral(node, shared, literal, literals[varName+"Info"]) {
+			mismatches = append(mismatches, fmt.Sprintf("%s::%s: %s", module.Name, node.Name, mismatch))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return errors.Errorf("Generated code diverges from module %s:\n%s", module.Name, strings.Join(mismatches, "\n"))
+	}
+
+	return nil
+}
+
+// compareNodeLiteral compares node to the literal of its node var and to info,
+// the literal of its NodeInfo, which is nil if there is none.
+func compareNodeLiteral(node gosmi.SmiNode, shared *sharedDecls, literal, info *ast.CompositeLit) (mismatches []string) {
+	fields := literalFields(literal)
+	var infoFields map[string]ast.Expr
+	if info != nil {
+		infoFields = literalFields(info)
+	}
+	oid, oidFormatted, oidLen := instanceOid(node)
+
+	if name := stringField(fields["Name"]); name != node.Name {
+		mismatches = append(mismatches, fmt.Sprintf("Name is %q, expected %q", name, node.Name))
+	}
+	if formatted := stringField(fields["OidFormatted"]); formatted != oidFormatted {
+		mismatches = append(mismatches, fmt.Sprintf("OidFormatted is %q, expected %q", formatted, oidFormatted))
+	}
+	if length, _ := strconv.Atoi(basicLitValue(fields["OidLen"])); length != oidLen {
+		mismatches = append(mismatches, fmt.Sprintf("OidLen is %d, expected %d", length, oidLen))
+	}
+
+	var subIDs []string
+	if oidLiteral, ok := fields["Oid"].(*ast.CompositeLit); ok {
+		for _, elt := range oidLiteral.Elts {
+			subID, err := strconv.ParseUint(basicLitValue(elt), 0, 32)
+			if err != nil {
+				subIDs = append(subIDs, "?")
+				continue
+			}
+			subIDs = append(subIDs, strconv.FormatUint(subID, 10))
+		}
+	}
+	if generated := strings.Join(subIDs, "."); generated != oid.String() {
+		mismatches = append(mismatches, fmt.Sprintf("Oid is %s, expected %s", generated, oid))
+	}
+
+	if node.Kind&(types.NodeColumn|types.NodeScalar) == 0 {
+		return
+	}
+
+	expectedAccess := "Access" + node.Access.String()
+	if access, ok := infoFields["Access"].(*ast.SelectorExpr); emitNodeInfo && (!ok || access.Sel.Name != expectedAccess) {
+		mismatches = append(mismatches, fmt.Sprintf("Access is not %s", expectedAccess))
+	}
+
+	switch t := fields["Type"].(type) {
+	case *ast.Ident:
+		if expected := formatTypeVarName(shared.typeName(node)); t.Name != expected {
+			mismatches = append(mismatches, fmt.Sprintf("Type is %s, expected %s", t.Name, expected))
+		}
+	case *ast.CompositeLit:
+		typeFields := literalFields(t)
+		if name := stringField(typeFields["Name"]); name != node.Type.Name {
+			mismatches = append(mismatches, fmt.Sprintf("Type name is %q, expected %q", name, node.Type.Name))
+		}
+		expected := "BaseType" + node.Type.BaseType.String()
+		if baseType, ok := typeFields["BaseType"].(*ast.SelectorExpr); !ok || baseType.Sel.Name != expected {
+			mismatches = append(mismatches, fmt.Sprintf("Type base type is not %s", expected))
+		}
+	default:
+		mismatches = append(mismatches, "Type not found")
+	}
+
+	return
+}
+
+// literalFields returns the keyed fields of literal, including those of the
+// embedded ScalarNode and BaseNode structs.
+func literalFields(literal *ast.CompositeLit) map[string]ast.Expr {
+	fields := make(map[string]ast.Expr)
+	for _, elt := range literal.Elts {
+		keyValue, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := keyValue.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if embedded, ok := keyValue.Value.(*ast.CompositeLit); ok && (key.Name == "ScalarNode" || key.Name == "BaseNode") {
+			for name, value := range literalFields(embedded) {
+				fields[name] = value
+			}
+			continue
+		}
+		fields[key.Name] = keyValue.Value
+	}
+	return fields
+}
+
+func basicLitValue(expr ast.Expr) string {
+	if literal, ok := expr.(*ast.BasicLit); ok {
+		return literal.Value
+	}
+	return ""
+}
+
+func stringField(expr ast.Expr) string {
+	s, err := strconv.Unquote(basicLitValue(expr))
+	if err != nil {
+		return ""
+	}
+	return s
+}
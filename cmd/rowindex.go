@@ -0,0 +1,41 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import "github.com/sleepinggenius2/gosmi"
+
+// rowIndex returns the index columns of row. gosmi resolves AUGMENTS, so a row
+// augmenting another has the index of that row, which is returned as base.
+func rowIndex(row gosmi.SmiNode) (index []gosmi.SmiNode, base gosmi.SmiNode, inherited bool) {
+	if augmented := row.GetAugment(); augmented != nil {
+		base, inherited = *augmented, true
+	}
+	return row.GetIndex(), base, inherited
+}
+
+// rowImplied reports whether the last index column of row is IMPLIED. A row
+// augmenting another shares its IMPLIED as well.
+func rowImplied(row gosmi.SmiNode) bool {
+	if augmented := row.GetAugment(); augmented != nil {
+		return rowImplied(*augmented)
+	}
+	return row.GetImplied()
+}
@@ -0,0 +1,92 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sleepinggenius2/gosmi"
+)
+
+const smiTypeDecls = `// Counter32 is a value of the SMI type Counter32, which wraps around to 0
+// after 2^32-1.
+type Counter32 uint32
+
+// Counter64 is a value of the SMI type Counter64, which wraps around to 0
+// after 2^64-1.
+type Counter64 uint64
+
+// Gauge32 is a value of the SMI type Gauge32, which latches at its maximum
+// instead of wrapping around.
+type Gauge32 uint32
+
+// TimeTicks is a value of the SMI type TimeTicks, in hundredths of a second.
+type TimeTicks uint32
+
+`
+
+// timeTicksDecls are emitted with --smi-types if any node generated is of type
+// TimeTicks.
+const timeTicksDecls = `// Duration returns t as a time.Duration.
+func (t TimeTicks) Duration() time.Duration {
+	return time.Duration(t) * 10 * time.Millisecond
+}
+
+`
+
+// smiApplicationTypes maps the SMI application types given distinct Go types
+// with --smi-types, their SMIv1 names and the textual conventions of standard
+// MIBs derived from them to those types.
+var smiApplicationTypes = map[string]string{
+	"Counter":   "Counter32",
+	"Counter32": "Counter32",
+	"Counter64": "Counter64",
+	"Gauge":     "Gauge32",
+	"Gauge32":   "Gauge32",
+	"TimeTicks": "TimeTicks",
+
+	"TimeStamp":          "TimeTicks", // SNMPv2-TC
+	"ZeroBasedCounter32": "Gauge32",   // RMON2-MIB
+	"ZeroBasedCounter64": "Counter64", // HCNUM-TC
+}
+
+// smiApplicationType returns the Go type of the SMI application type the type
+// of node is. gosmi doesn't expose the type a textual convention is derived
+// from, so other textual conventions keep their base type.
+func smiApplicationType(node gosmi.SmiNode) (string, bool) {
+	if node.Type == nil {
+		return "", false
+	}
+	typeName, ok := smiApplicationTypes[node.Type.Name]
+	return typeName, ok
+}
+
+// generateSmiTypeAlias emits typeName as an alias of the Go type of the SMI
+// application type of node, if it has one.
+func generateSmiTypeAlias(buf io.Writer, typeName string, node gosmi.SmiNode) {
+	smiType, ok := smiApplicationType(node)
+	if !ok {
+		return
+	}
+	fmt.Fprintf(buf, "// %s is a value of %s.\n", typeName, node.Name)
+	fmt.Fprintf(buf, "type %s = %s\n\n", typeName, smiType)
+}
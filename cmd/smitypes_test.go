@@ -0,0 +1,67 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import "testing"
+
+func TestTimeTicksDuration(t *testing.T) {
+	main := "func main() { fmt.Println(TimeTicks(4200).Duration()) }\n"
+	if got := runGenerated(t, imports{"time": true}, smiTypeDecls+timeTicksDecls, main); got != "42s" {
+		t.Errorf("TimeTicks(4200).Duration() is %s, expected 42s", got)
+	}
+}
+
+func TestSmiTypeDecls(t *testing.T) {
+	main := `func main() {
+	var counter32 Counter32 = 1<<32 - 1
+	counter32++
+	var counter64 Counter64 = 1<<64 - 1
+	counter64++
+	fmt.Println(counter32, counter64)
+}
+`
+	if got := runGenerated(t, imports{}, smiTypeDecls, main); got != "0 0" {
+		t.Errorf("Counters are %s after wrapping around, expected 0 0", got)
+	}
+}
+
+func TestSmiTypes(t *testing.T) {
+	generated := generateFixture(t, "augments", "--native-types", "--smi-types")
+	assertContains(t, generated, "\tFixtureAugCount Counter32\n", "type Counter32 uint32\n")
+
+	generated = generateFixture(t, "augments", "--native-types")
+	assertContains(t, generated, "\tFixtureAugCount uint32\n")
+	assertNotContains(t, generated, "type Counter32 uint32\n")
+}
+
+func TestSmiTypesWithoutNativeTypes(t *testing.T) {
+	generated := generateFixture(t, "augments", "--smi-types")
+	assertContains(t, generated, "type FixtureAugCountValue = Counter32\n")
+	assertNotContains(t, generated, "FixtureAugCount uint32")
+
+	output := runFixture(t, "interfaces", `func main() {
+	var lastChange FixtureIfLastChangeValue = 4200
+	fmt.Println(lastChange.Duration())
+}`, "--smi-types")
+	if output != "42s" {
+		t.Errorf("fixtureIfLastChange is %s as a TimeTicks, expected 42s", output)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// standaloneDecls replace the gosmi models and types packages with --standalone.
+// They mirror the parts of those packages the generated code uses and only
+// ever change in step with the generator.
+const standaloneDecls = `// Oid is an object identifier.
+type Oid []uint32
+
+// String returns o in dotted decimal notation.
+func (o Oid) String() string {
+	subIDs := make([]string, len(o))
+	for i, subID := range o {
+		subIDs[i] = strconv.FormatUint(uint64(subID), 10)
+	}
+	return strings.Join(subIDs, ".")
+}
+
+// BaseType is the base type a type is derived from.
+type BaseType int
+
+const (
+	BaseTypeUnknown BaseType = iota
+	BaseTypeInteger32
+	BaseTypeOctetString
+	BaseTypeObjectIdentifier
+	BaseTypeUnsigned32
+	BaseTypeInteger64
+	BaseTypeUnsigned64
+	BaseTypeFloat32
+	BaseTypeFloat64
+	BaseTypeFloat128
+	BaseTypeEnum
+	BaseTypeBits
+	BaseTypePointer
+)
+
+// Language is the language a module is written in.
+type Language int
+
+const (
+	LanguageUnknown Language = iota
+	LanguageSMIv1
+	LanguageSMIv2
+	LanguageSMIng
+	LanguageSPPI
+)
+
+// Access is the MAX-ACCESS of an object.
+type Access int
+
+const (
+	AccessUnknown Access = iota
+	AccessNotImplemented
+	AccessNotAccessible
+	AccessNotify
+	AccessReadOnly
+	AccessReadWrite
+	AccessInstall
+	AccessInstallNotify
+	AccessReportOnly
+	AccessEventOnly
+)
+
+// Status is the STATUS of a definition.
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusCurrent
+	StatusDeprecated
+	StatusMandatory
+	StatusOptional
+	StatusObsolete
+)
+
+// Range is a value or size range of a type. SizeConstraint is set for the
+// size ranges of octet strings and BITS, which constrain their length.
+type Range struct {
+	BaseType       BaseType
+	MinValue       int64
+	MaxValue       int64
+	SizeConstraint bool
+}
+
+// EnumValues maps the values of an enumeration to their labels.
+type EnumValues map[int64]string
+
+// Enum holds the values of an enumeration or the bits of a BITS type.
+type Enum struct {
+	BaseType BaseType
+	Values   EnumValues
+}
+
+// Type is the type of a scalar or column.
+type Type struct {
+	BaseType BaseType
+	Enum     *Enum
+	Format   string
+	Name     string
+	Ranges   []Range
+	Units    string
+}
+
+// Revision is a REVISION of a module.
+type Revision struct {
+	Date        time.Time
+	Description string
+}
+
+// BaseNode holds what all nodes have in common.
+type BaseNode struct {
+	Name         string
+	Oid          Oid
+	OidFormatted string
+	OidLen       uint
+}
+
+// ScalarNode is a scalar object.
+type ScalarNode struct {
+	BaseNode
+	Type Type
+}
+
+// ColumnNode is a column of a table.
+type ColumnNode struct {
+	ScalarNode
+}
+
+// RowNode is the entry of a table.
+type RowNode struct {
+	BaseNode
+	Columns []ColumnNode
+	Index   []ColumnNode
+	Implied bool
+}
+
+// TableNode is a table.
+type TableNode struct {
+	BaseNode
+	Row RowNode
+}
+
+// NotificationNode is a notification, or an SMIv1 trap.
+type NotificationNode struct {
+	BaseNode
+	Objects []ScalarNode
+}
+
+`
+
+// stripGosmiQualifiers removes the models and types package qualifiers from
+// the generated declarations in b, so they refer to the standaloneDecls
+// instead. The qualifiers are located by parsing b, which keeps names and
+// text in descriptions and strings untouched.
+func stripGosmiQualifiers(b []byte) ([]byte, error) {
+	const packageClause = "package p\n"
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", append([]byte(packageClause), b...), 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "Parsing generated source")
+	}
+
+	type span struct{ start, end int }
+	var qualifiers []span
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && (x.Name == "models" || x.Name == "types") {
+			qualifiers = append(qualifiers, span{
+				start: fset.Position(x.Pos()).Offset - len(packageClause),
+				end:   fset.Position(sel.Sel.Pos()).Offset - len(packageClause),
+			})
+		}
+		return true
+	})
+	sort.Slice(qualifiers, func(i, j int) bool { return qualifiers[i].start < qualifiers[j].start })
+
+	stripped := make([]byte, 0, len(b))
+	last := 0
+	for _, qualifier := range qualifiers {
+		stripped = append(stripped, b[last:qualifier.start]...)
+		last = qualifier.end
+	}
+	return append(stripped, b[last:]...), nil
+}
@@ -0,0 +1,177 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// typesSubpackage is the subpackage the shared declarations are generated into
+// with --subpackages. It isn't named types, which would clash with the gosmi
+// types package every module imports.
+const typesSubpackage = "mibtypes"
+
+// subpackageFile is a module generated with --subpackages, which is written
+// once the shared declarations its references are qualified with are known.
+type subpackageFile struct {
+	name    string
+	imports imports
+	body    []byte
+}
+
+// subpackageName returns the name of the subpackage a module is generated into
+// with --subpackages, which is also the name of its directory and file.
+func subpackageName(moduleName string) string {
+	return sanitizeIdentifier(strings.ToLower(formatModuleName(moduleName)))
+}
+
+// declaredNames returns the names of the top-level declarations in the
+// generated source b.
+func declaredNames(b []byte) (map[string]bool, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", append([]byte("package p\n"), b...), 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "Parsing generated source")
+	}
+
+	names := make(map[string]bool)
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				names[decl.Name.Name] = true
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						names[name.Name] = true
+					}
+				case *ast.TypeSpec:
+					names[spec.Name.Name] = true
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+// qualifySubpackageRefs qualifies the references of the generated module
+// source in f to the shared declarations named in typesNames with the types
+// subpackage, and adds the imports of that and of the module subpackages in
+// packages, keyed by name, it references. It returns the names of the module
+// subpackages referenced. References are found by parsing the source, as
+// those are the identifiers that don't resolve within the file.
+func qualifySubpackageRefs(f *subpackageFile, typesNames map[string]bool, packages map[string]string) ([]string, error) {
+	const packageClause = "package p\n"
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", append([]byte(packageClause), f.body...), 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "Parsing generated source")
+	}
+
+	var offsets []int
+	referenced := make(map[string]bool)
+	for _, ident := range file.Unresolved {
+		if typesNames[ident.Name] {
+			offsets = append(offsets, fset.Position(ident.Pos()).Offset-len(packageClause))
+		} else if subpackagePath, ok := packages[ident.Name]; ok && ident.Name != f.name {
+			f.imports.add(subpackagePath)
+			referenced[ident.Name] = true
+		}
+	}
+	sort.Ints(offsets)
+
+	if len(offsets) > 0 {
+		f.imports.add(path.Join(importPath, typesSubpackage))
+		qualified := make([]byte, 0, len(f.body)+len(offsets)*(len(typesSubpackage)+1))
+		last := 0
+		for _, offset := range offsets {
+			qualified = append(qualified, f.body[last:offset]...)
+			qualified = append(qualified, typesSubpackage+"."...)
+			last = offset
+		}
+		f.body = append(qualified, f.body[last:]...)
+	}
+
+	names := make([]string, 0, len(referenced))
+	for name := range referenced {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// findImportCycle returns the subpackages or modules forming an import cycle
+// in deps, which maps each of them to those it imports, starting and ending
+// with the same one, or nil if there is none.
+func findImportCycle(deps map[string][]string) []string {
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var stack []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			for i := range stack {
+				if stack[i] == name {
+					return append(append([]string{}, stack[i:]...), name)
+				}
+			}
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range deps[name] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, name := range names {
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSubpackages(t *testing.T) {
+	dir, err := ioutil.TempDir(filepath.Join("..", "testdata"), "generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	importPath := "github.com/sleepinggenius2/mib2go/testdata/" + filepath.Base(dir)
+	err = generateSources(fixtureSources(t, "subpackages"), []string{"--dir", dir, "--subpackages", "--import-path", importPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := exec.Command("go", "build", "./"+filepath.ToSlash(dir)+"/...").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Building the generated subpackages: %v\n%s", err, output)
+	}
+
+	ext, err := ioutil.ReadFile(filepath.Join(dir, "fixtureextmib", "fixtureextmib.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, string(ext),
+		"package fixtureextmib\n",
+		"\""+importPath+"/fixturebasemib\"",
+		"\""+importPath+"/mibtypes\"",
+		"fixturebasemib.FixtureBaseMib.FixtureIndex",
+		"mibtypes.FixtureNameType",
+	)
+	if _, err := os.Stat(filepath.Join(dir, "mibtypes", "types.go")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSubpackagesSinglePackageFlags(t *testing.T) {
+	for _, flag := range []string{"--oid-index", "--modules-map", "--descriptions-file"} {
+		err := generateSources(fixtureSources(t, "subpackages"), []string{"--dir", os.DevNull, "--subpackages", "--import-path", "example.com/mibs", flag})
+		if err == nil || !strings.Contains(err.Error(), "can't be used with --subpackages") {
+			t.Errorf("Expected %s to be rejected with --subpackages, got %v", flag, err)
+		}
+	}
+}
+
+func TestFindImportCycle(t *testing.T) {
+	for _, test := range []struct {
+		deps map[string][]string
+		want []string
+	}{
+		{map[string][]string{"A": {"B"}, "B": {"C"}, "C": nil}, nil},
+		{map[string][]string{"A": {"B", "C"}, "B": {"C"}, "C": nil}, nil},
+		{map[string][]string{"A": {"B"}, "B": {"C"}, "C": {"A"}}, []string{"A", "B", "C", "A"}},
+		{map[string][]string{"A": {"B"}, "B": {"C"}, "C": {"B"}}, []string{"B", "C", "B"}},
+		{map[string][]string{"A": {"A"}}, []string{"A", "A"}},
+	} {
+		if cycle := findImportCycle(test.deps); !reflect.DeepEqual(cycle, test.want) {
+			t.Errorf("findImportCycle(%v) = %v, want %v", test.deps, cycle, test.want)
+		}
+	}
+}
+
+func TestQualifySubpackageRefs(t *testing.T) {
+	defer func(path string) { importPath = path }(importPath)
+	importPath = "example.com/mibs"
+
+	typesNames, err := declaredNames([]byte("var FixtureNameType = models.Type{}\n\ntype OidRange struct{}\n\nfunc Register(name string, module interface{}) {}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &subpackageFile{name: "fixtureextmib", imports: imports{}, body: []byte(`var fixtureExtNameNode = models.ColumnNode{
+	ScalarNode: models.ScalarNode{Type: FixtureNameType},
+}
+var fixtureExtEntryNode = models.RowNode{
+	Index: []models.ColumnNode{fixturebasemib.FixtureBaseMib.FixtureIndex},
+}
+var fixtureExtTableRange = OidRange{}
+
+func init() {
+	Register("FIXTURE-EXT-MIB", FixtureExtMib)
+}
+`)}
+	packages := map[string]string{
+		"fixturebasemib": "example.com/mibs/fixturebasemib",
+		"fixtureextmib":  "example.com/mibs/fixtureextmib",
+	}
+	deps, err := qualifySubpackageRefs(f, typesNames, packages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"fixturebasemib"}; !reflect.DeepEqual(deps, want) {
+		t.Errorf("Dependencies are %v, want %v", deps, want)
+	}
+
+	body := string(f.body)
+	for _, want := range []string{"Type: mibtypes.FixtureNameType", "= mibtypes.OidRange{", "\tmibtypes.Register(\"FIXTURE-EXT-MIB\""} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Qualified body doesn't contain %q:\n%s", want, body)
+		}
+	}
+	want := imports{"example.com/mibs/mibtypes": true, "example.com/mibs/fixturebasemib": true}
+	if !reflect.DeepEqual(f.imports, want) {
+		t.Errorf("Imports are %v, want %v", f.imports, want)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sleepinggenius2/gosmi"
+)
+
+const gosnmpImport = "github.com/gosnmp/gosnmp"
+
+// trapDecoderDecls are emitted once into the types file with --trap-decoders
+// and used by the decoder generated for every notification.
+const trapDecoderDecls = `// trapObject is an object declared by a notification, with the OID of the
+// object its varbinds are instances of.
+type trapObject struct {
+	name string
+	oid  string
+}
+
+// MissingTrapObjectsError is returned by the trap decoders along with the
+// decoded varbinds, naming the declared objects no varbind was received for.
+type MissingTrapObjectsError []string
+
+func (e MissingTrapObjectsError) Error() string {
+	return "Missing objects " + strings.Join(e, ", ")
+}
+
+// decodeTrap maps vbs to objects by OID, keyed by object name. Varbinds are
+// matched by the instances of the objects, like the .0 of a scalar, and those
+// matching none of them, like sysUpTime.0 and snmpTrapOID.0, are skipped.
+func decodeTrap(objects []trapObject, vbs []gosnmp.SnmpPDU) (map[string]interface{}, error) {
+	decoded := make(map[string]interface{}, len(objects))
+	for _, vb := range vbs {
+		oid := strings.TrimPrefix(vb.Name, ".")
+		for _, object := range objects {
+			if oid == object.oid || strings.HasPrefix(oid, object.oid+".") {
+				decoded[object.name] = vb.Value
+				break
+			}
+		}
+	}
+
+	var missing MissingTrapObjectsError
+	for _, object := range objects {
+		if _, ok := decoded[object.name]; !ok {
+			missing = append(missing, object.name)
+		}
+	}
+	if len(missing) > 0 {
+		return decoded, missing
+	}
+	return decoded, nil
+}
+
+`
+
+// generateTrapDecoder emits the objects declared by notification, in the order
+// they are declared, and the decoder of the varbinds of the notification.
+func generateTrapDecoder(buf io.Writer, imports imports, shared *sharedDecls, notification gosmi.SmiNode) {
+	imports.add(gosnmpImport)
+	shared.trapDecoders = true
+	varName := shared.nodeVarName(notification.GetModule().Name, notification.Name)
+	objectsName := lowerFirst(varName) + "TrapObjects"
+
+	fmt.Fprintf(buf, "var %s = []trapObject{\n", objectsName)
+	for _, object := range notification.GetNotificationObjects() {
+		if skipped(object) {
+			continue
+		}
+		fmt.Fprintf(buf, "\t{name: %q, oid: %q},\n", object.Name, object.RenderNumeric())
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	decoderName := "Decode" + upperFirst(varName) + "Trap"
+	fmt.Fprintf(buf, "// %s maps the varbinds of a %s notification to the objects it\n", decoderName, notification.Name)
+	fmt.Fprintf(buf, "// declares, keyed by object name.\n")
+	fmt.Fprintf(buf, "func %s(vbs []gosnmp.SnmpPDU) (map[string]interface{}, error) {\n", decoderName)
+	fmt.Fprintf(buf, "\treturn decodeTrap(%s, vbs)\n", objectsName)
+	fmt.Fprintf(buf, "}\n\n")
+}
@@ -0,0 +1,134 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	treeNumeric bool
+	treeDepth   int
+)
+
+// treeCmd represents the tree command
+var treeCmd = &cobra.Command{
+	Use:   "tree MODULE...",
+	Short: "Prints the node tree of MIBs",
+	Long: `Loads MIBs the same way generate does and prints the OID hierarchy of the
+nodes of each module, indented by their depth within the module, with their
+name, the last sub-identifier of their OID, their kind and their type, if they
+have one. With --numeric, the full OID is printed instead of the last
+sub-identifier, and --depth limits how many levels are printed.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := applyConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		return tree(args, os.Stdout)
+	},
+}
+
+func tree(args []string, out io.Writer) error {
+	if treeDepth < 0 {
+		return errors.Errorf("Invalid depth %d", treeDepth)
+	}
+
+	gosmi.Init()
+	defer gosmi.Exit()
+
+	setSearchPath(nil)
+
+	tempDir, err := ioutil.TempDir("", "mib2go")
+	if err != nil {
+		return errors.Wrap(err, "Creating temporary directory")
+	}
+	defer os.RemoveAll(tempDir)
+	defer removeOnInterrupt(tempDir)()
+
+	modules, err := loadModules(args, tempDir)
+	if err != nil {
+		return err
+	}
+
+	for _, module := range modules {
+		printTree(out, module.Name, module.GetNodes())
+	}
+
+	return nil
+}
+
+// printTree prints the nodes of the module moduleName as a tree. Nodes are
+// nested below the closest node of the module their OID starts with, and
+// those without one start at the top level.
+func printTree(out io.Writer, moduleName string, nodes []gosmi.SmiNode) {
+	sorted := append([]gosmi.SmiNode(nil), nodes...)
+	sortNodes(sorted)
+
+	fmt.Fprintln(out, moduleName)
+	var ancestors []models.Oid
+	for _, node := range sorted {
+		for len(ancestors) > 0 && !node.Oid.ChildOf(ancestors[len(ancestors)-1]) {
+			ancestors = ancestors[:len(ancestors)-1]
+		}
+		depth := len(ancestors)
+		ancestors = append(ancestors, node.Oid)
+		if treeDepth > 0 && depth >= treeDepth {
+			continue
+		}
+
+		for i := 0; i <= depth; i++ {
+			fmt.Fprint(out, "  ")
+		}
+		var subID string
+		if treeNumeric {
+			subID = node.Oid.String()
+		} else if len(node.Oid) > 0 {
+			subID = fmt.Sprint(node.Oid[len(node.Oid)-1])
+		}
+		fmt.Fprintf(out, "%s(%s) %s", node.Name, subID, node.Kind)
+		if node.Type != nil && node.Type.Name != "" {
+			fmt.Fprintf(out, " %s", node.Type.Name)
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(treeCmd)
+
+	flags := treeCmd.Flags()
+	flags.StringSliceVarP(&paths, "path", "M", []string{}, "Path(s) to add to MIB search path")
+	flags.StringSliceVar(&overrides, "override-path", []string{}, "Path(s) searched for MIBs before the default and -M paths, in the order given")
+	flags.BoolVar(&treeNumeric, "numeric", false, "Print the full OID of each node instead of its last sub-identifier")
+	flags.IntVar(&treeDepth, "depth", 0, "Number of levels to print, 0 for all")
+}
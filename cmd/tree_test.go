@@ -0,0 +1,74 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	paths = []string{filepath.Join("..", "testdata", "interfaces")}
+	defer func() { paths, treeNumeric, treeDepth = nil, false, 0 }()
+
+	buf := &bytes.Buffer{}
+	err := tree([]string{"FIXTURE-IF-MIB"}, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `FIXTURE-IF-MIB
+  fixtureIfMib(59) Node
+    ifNumber(1) Scalar Integer32
+    ifTable(2) Table IfEntry
+      ifEntry(1) Row IfEntry
+        ifIndex(1) Column Integer32
+        ifDescr(2) Column DisplayString
+    fixtureIfLastChange(3) Scalar TimeTicks
+    fixtureIfLinkDown(4) Notification
+`
+	if buf.String() != want {
+		t.Errorf("Got\n%s\nexpected\n%s", buf, want)
+	}
+
+	treeNumeric, treeDepth = true, 2
+	buf.Reset()
+	err = tree([]string{"FIXTURE-IF-MIB"}, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `FIXTURE-IF-MIB
+  fixtureIfMib(1.3.6.1.4.1.99999.59) Node
+    ifNumber(1.3.6.1.4.1.99999.59.1) Scalar Integer32
+    ifTable(1.3.6.1.4.1.99999.59.2) Table IfEntry
+    fixtureIfLastChange(1.3.6.1.4.1.99999.59.3) Scalar TimeTicks
+    fixtureIfLinkDown(1.3.6.1.4.1.99999.59.4) Notification
+`
+	if buf.String() != want {
+		t.Errorf("Got\n%s\nexpected\n%s", buf, want)
+	}
+
+	treeDepth = -1
+	err = tree([]string{"FIXTURE-IF-MIB"}, buf)
+	if err == nil {
+		t.Error("Expected a negative depth to be rejected")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/types"
+	"github.com/spf13/cobra"
+)
+
+// unusedNodeKinds are the kinds of nodes that are expected in MIBs, but aren't
+// generated, so validate doesn't warn about them.
+const unusedNodeKinds = types.NodeNode | types.NodeCapabilities
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Checks MIBs without generating code",
+	Long: `Loads MIBs the same way generate does and checks their nodes without
+generating code. Nodes of unknown kinds, scalars and columns without a type
+and rows without an index are reported, followed by a summary per module.
+Exits with a non-zero status if any problems were found.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := applyConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		return validate(args)
+	},
+}
+
+func validate(args []string) error {
+	gosmi.Init()
+	defer gosmi.Exit()
+
+	setSearchPath(nil)
+
+	tempDir, err := ioutil.TempDir("", "mib2go")
+	if err != nil {
+		return errors.Wrap(err, "Creating temporary directory")
+	}
+	defer os.RemoveAll(tempDir)
+	defer removeOnInterrupt(tempDir)()
+
+	modules, err := loadModules(args, tempDir)
+	if err != nil {
+		return err
+	}
+
+	var problems int
+	for _, module := range modules {
+		nodes := module.GetNodes()
+		warnings := validateNodes(module, nodes)
+		for _, warning := range warnings {
+			fmt.Println(warning)
+		}
+		fmt.Printf("%s: %d nodes, %d warnings\n", module.Name, len(nodes), len(warnings))
+		problems += len(warnings)
+	}
+
+	if problems > 0 {
+		return errors.Errorf("Found %d problems", problems)
+	}
+
+	return nil
+}
+
+func validateNodes(module gosmi.SmiModule, nodes []gosmi.SmiNode) (warnings []string) {
+	for _, node := range nodes {
+		switch {
+		case node.Kind&(allowedNodeKinds|unusedNodeKinds) == 0:
+			warnings = append(warnings, fmt.Sprintf("%s::%s: Unknown node kind %s", module.Name, node.Name, node.Kind))
+		case node.Kind&(types.NodeScalar|types.NodeColumn) > 0 && node.Type == nil:
+			warnings = append(warnings, fmt.Sprintf("%s::%s: %s without a type", module.Name, node.Name, node.Kind))
+		case node.Kind == types.NodeRow && !hasIndex(node):
+			warnings = append(warnings, fmt.Sprintf("%s::%s: Row without an index", module.Name, node.Name))
+		}
+	}
+	return
+}
+
+func hasIndex(row gosmi.SmiNode) bool {
+	index, _, _ := rowIndex(row)
+	return len(index) > 0
+}
+
+func init() {
+	RootCmd.AddCommand(validateCmd)
+
+	flags := validateCmd.Flags()
+	flags.StringSliceVarP(&paths, "path", "M", []string{}, "Path(s) to add to MIB search path")
+	flags.StringSliceVar(&overrides, "override-path", []string{}, "Path(s) searched for MIBs before the default and -M paths, in the order given")
+}
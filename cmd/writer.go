@@ -0,0 +1,77 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"sync"
+)
+
+// fileWriter writes the files generated per module on up to --jobs goroutines,
+// as formatting is what takes longest for large modules. libsmi isn't safe for
+// concurrent use, so the files are still generated one after another.
+type fileWriter struct {
+	jobs chan struct{}
+	wg   sync.WaitGroup
+
+	mu   sync.Mutex
+	errs []error
+}
+
+func newFileWriter(jobs int) *fileWriter {
+	return &fileWriter{jobs: make(chan struct{}, jobs)}
+}
+
+// write writes body to filename like writeGeneratedFile, blocking while all
+// jobs are busy. Errors are returned by wait.
+func (w *fileWriter) write(filename string, filePackage string, imports imports, body []byte) {
+	w.mu.Lock()
+	i := len(w.errs)
+	w.errs = append(w.errs, nil)
+	w.mu.Unlock()
+
+	w.jobs <- struct{}{}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		err := writeGeneratedFile(filename, nil, filePackage, imports, body)
+		<-w.jobs
+
+		w.mu.Lock()
+		w.errs[i] = err
+		w.mu.Unlock()
+	}()
+}
+
+// wait waits for the files being written and returns the errors of those that
+// failed, in the order they were passed to write rather than the order the
+// jobs finished in.
+func (w *fileWriter) wait() []error {
+	w.wg.Wait()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var errs []error
+	for _, err := range w.errs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
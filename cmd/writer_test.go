@@ -0,0 +1,128 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// jobsSources returns n modules of 50 scalars each, enough for formatting to
+// dominate writing their files.
+func jobsSources(n int) map[string]string {
+	sources := make(map[string]string)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("JOBS-TEST-%d-MIB", i)
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s DEFINITIONS ::= BEGIN\n", name)
+		fmt.Fprintf(&b, "IMPORTS OBJECT-TYPE, Integer32, enterprises FROM SNMPv2-SMI;\n")
+		fmt.Fprintf(&b, "jobsTest%d OBJECT IDENTIFIER ::= { enterprises 99999 %d }\n", i, 100+i)
+		for j := 1; j <= 50; j++ {
+			fmt.Fprintf(&b, "jobsTest%dScalar%d OBJECT-TYPE\n", i, j)
+			fmt.Fprintf(&b, "    SYNTAX Integer32\n    MAX-ACCESS read-only\n    STATUS current\n")
+			fmt.Fprintf(&b, "    DESCRIPTION \"Scalar %d of module %d.\"\n", j, i)
+			fmt.Fprintf(&b, "    ::= { jobsTest%d %d }\n", i, j)
+		}
+		fmt.Fprintf(&b, "END\n")
+		sources[name] = b.String()
+	}
+	return sources
+}
+
+// generateJobs generates sources into a new temporary directory on the given
+// number of jobs and returns the directory.
+func generateJobs(t testing.TB, sources map[string]string, jobs int) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = generateSources(sources, []string{"--dir", dir, "--jobs", fmt.Sprint(jobs)})
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// TestJobs checks that writing files on several jobs gives the same files as
+// writing them one after another. Run with -race, it also checks that the
+// jobs don't race on the state of the run.
+func TestJobs(t *testing.T) {
+	sources := jobsSources(20)
+	sequential := generateJobs(t, sources, 1)
+	defer os.RemoveAll(sequential)
+	parallel := generateJobs(t, sources, 8)
+	defer os.RemoveAll(parallel)
+
+	filenames, err := filepath.Glob(filepath.Join(sequential, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filenames) < len(sources) {
+		t.Fatalf("Expected a file per module, got %v", filenames)
+	}
+	for _, filename := range filenames {
+		want, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ioutil.ReadFile(filepath.Join(parallel, filepath.Base(filename)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s differs when written on several jobs", filepath.Base(filename))
+		}
+	}
+}
+
+func TestFileWriterErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	w := newFileWriter(4)
+	w.write(filepath.Join(dir, "valid.go"), "mibs", imports{}, []byte("var valid = 1\n"))
+	w.write(filepath.Join(dir, "invalid.go"), "mibs", imports{}, []byte("var invalid = \n"))
+	errs := w.wait()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "expected") {
+		t.Fatalf("Expected the error of the invalid file only, got %v", errs)
+	}
+}
+
+func BenchmarkJobs(b *testing.B) {
+	sources := jobsSources(20)
+	for _, jobs := range []int{1, 4} {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				os.RemoveAll(generateJobs(b, sources, jobs))
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package mib2go generates Go code from MIB modules, like the mib2go command.
+package mib2go
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/sleepinggenius2/mib2go/cmd"
+)
+
+// Options configures GenerateFromSources.
+type Options struct {
+	// Flags are flags of the generate command, e.g. "--package=foo". Options
+	// not given here keep their defaults, unless set by ConfigFile.
+	Flags []string
+
+	// ConfigFile is a config file whose generate section sets the options not
+	// given in Flags, like the --config flag of the command line does.
+	ConfigFile string
+}
+
+// GenerateFromSources generates Go code from MIB modules given as source text
+// keyed by module name, configured by opts. The options of previous calls and
+// the flags of the mib2go command don't carry over.
+//
+// The sources are written to a temporary directory, which is searched before
+// any other path and removed again once generation has finished, whether it
+// failed or not, so callers never have to deal with input files themselves.
+// Module names are used as the names of those files, so they can't contain a
+// path separator.
+func GenerateFromSources(sources map[string]string, opts Options) error {
+	for moduleName := range sources {
+		if moduleName == "." || moduleName == ".." || filepath.Base(moduleName) != moduleName {
+			return errors.Errorf("Invalid module name %q", moduleName)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "mib2go-sources")
+	if err != nil {
+		return errors.Wrap(err, "Creating temporary directory")
+	}
+	defer os.RemoveAll(dir)
+
+	moduleNames := make([]string, 0, len(sources))
+	for moduleName, source := range sources {
+		filename := filepath.Join(dir, moduleName)
+		err = ioutil.WriteFile(filename, []byte(source), 0644)
+		if err != nil {
+			return errors.Wrapf(err, "Writing file %s", filename)
+		}
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+
+	return cmd.Generate(moduleNames, opts.Flags, opts.ConfigFile, dir)
+}
@@ -0,0 +1,73 @@
+// Copyright © 2017 sleepinggenius2 <sleepinggenius2@users.noreply.github.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package mib2go
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sourcesTestMib = `SOURCES-TEST-MIB DEFINITIONS ::= BEGIN
+IMPORTS enterprises FROM SNMPv2-SMI;
+sourcesTest OBJECT IDENTIFIER ::= { enterprises 99999 30 }
+END
+`
+
+func TestGenerateFromSources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = GenerateFromSources(map[string]string{"SOURCES-TEST-MIB": sourcesTestMib}, Options{Flags: []string{"--dir", dir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "sources-test-mib.go")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGenerateFromSourcesInvalidModuleName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mib2go-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, moduleName := range []string{"", ".", "..", "../SOURCES-TEST-MIB", "a/SOURCES-TEST-MIB", filepath.Join(dir, "SOURCES-TEST-MIB")} {
+		err = GenerateFromSources(map[string]string{moduleName: sourcesTestMib}, Options{Flags: []string{"--dir", dir}})
+		if err == nil || !strings.Contains(err.Error(), "Invalid module name") {
+			t.Errorf("Expected module name %q to be rejected, got %v", moduleName, err)
+		}
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) > 0 {
+		t.Errorf("Expected nothing to be written, got %s", entries[0].Name())
+	}
+}